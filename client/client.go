@@ -0,0 +1,461 @@
+// Package client is a Go client for the ETF API. It logs in, refreshes access
+// tokens before they expire, retries failed requests and returns the API
+// models, so callers do not have to declare them again.
+//
+//	c, err := client.New("http://localhost:8080", client.Config{
+//		Username: "reporting",
+//		Password: os.Getenv("ETF_API_PASSWORD"),
+//	})
+//	if err != nil {
+//		return err
+//	}
+//
+//	etf, err := c.GetETF(ctx, "SPY")
+//	if errors.Is(err, client.ErrNotFound) {
+//		...
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"awesomeProject/models"
+)
+
+const (
+	// DefaultMaxRetries is the number of retries used when Config.MaxRetries is zero.
+	DefaultMaxRetries = 3
+
+	// DefaultRetryBackoff is the first retry delay used when Config.RetryBackoff is zero.
+	DefaultRetryBackoff = 500 * time.Millisecond
+
+	// maxRetryDelay caps the delay between retries, also when the server asks for more.
+	maxRetryDelay = 30 * time.Second
+
+	// tokenExpiryMargin refreshes access tokens a bit before they expire,
+	// so they do not expire on the way to the server.
+	tokenExpiryMargin = 30 * time.Second
+)
+
+// Config configures a Client. Either Username and Password or APIKey have to be set.
+type Config struct {
+	// HTTPClient sends the requests, http.DefaultClient is used when it is nil.
+	HTTPClient *http.Client
+
+	// Username and Password are used to log in and to log in again when the refresh token expired.
+	Username string
+	Password string
+
+	// APIKey is sent in the X-API-Key header instead of logging in.
+	APIKey string
+
+	// MaxRetries is the number of times a request is retried after network
+	// errors, 429 and 502 to 504 responses. Negative values disable retries.
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, it doubles with every further retry.
+	RetryBackoff time.Duration
+}
+
+// Client calls the ETF API. It is safe for concurrent use.
+type Client struct {
+	baseURL *url.URL
+	config  Config
+
+	mu     sync.Mutex
+	tokens *models.TokenResponse
+}
+
+// New creates a client for the API at baseURL, e.g. "http://localhost:8080".
+// The client logs in lazily on the first request.
+func New(baseURL string, config Config) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme and host are required", baseURL)
+	}
+
+	if config.APIKey == "" && (config.Username == "" || config.Password == "") {
+		return nil, errors.New("either username and password or an API key are required")
+	}
+
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultMaxRetries
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = DefaultRetryBackoff
+	}
+
+	return &Client{baseURL: u, config: config}, nil
+}
+
+// ListETFs returns the tickers of all ETFs.
+func (c *Client) ListETFs(ctx context.Context) ([]string, error) {
+	var tickers []string
+	if err := c.do(ctx, http.MethodGet, "/secured/etfs", nil, &tickers); err != nil {
+		return nil, err
+	}
+	return tickers, nil
+}
+
+// GetETF returns the data of one ETF. It returns an error matching
+// ErrNotFound when there is no ETF with the ticker.
+func (c *Client) GetETF(ctx context.Context, ticker string) (*models.ETFData, error) {
+	if ticker == "" {
+		return nil, errors.New("ticker is required")
+	}
+
+	var etf models.ETFData
+	if err := c.do(ctx, http.MethodGet, "/secured/etf/"+url.PathEscape(ticker), nil, &etf); err != nil {
+		return nil, err
+	}
+	return &etf, nil
+}
+
+// ListHoldings returns a page of the full holdings of an ETF, pages start at 1.
+// A zero page or pageSize uses the server defaults. It returns an error
+// matching ErrNotFound when no holdings are stored for the ETF.
+func (c *Client) ListHoldings(ctx context.Context, ticker string, page, pageSize int) (*models.HoldingsPage, error) {
+	if ticker == "" {
+		return nil, errors.New("ticker is required")
+	}
+
+	query := url.Values{}
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	if pageSize > 0 {
+		query.Set("page_size", strconv.Itoa(pageSize))
+	}
+
+	path := "/secured/etf/" + url.PathEscape(ticker) + "/holdings"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var holdings models.HoldingsPage
+	if err := c.do(ctx, http.MethodGet, path, nil, &holdings); err != nil {
+		return nil, err
+	}
+	return &holdings, nil
+}
+
+// AllHoldings returns every position of the full holdings of an ETF, fetched
+// in pages of pageSize. A zero pageSize uses the server default.
+func (c *Client) AllHoldings(ctx context.Context, ticker string, pageSize int) (*models.HoldingsPage, error) {
+	all, err := c.ListHoldings(ctx, ticker, 1, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for page := 2; len(all.Holdings) < all.Total; page++ {
+		next, err := c.ListHoldings(ctx, ticker, page, all.PageSize)
+		if err != nil {
+			return nil, err
+		}
+		// The holdings were replaced by the updater between the pages
+		if next.AsOf != all.AsOf || next.Total != all.Total || len(next.Holdings) == 0 {
+			return nil, fmt.Errorf("the holdings of %s changed while reading them, try again", ticker)
+		}
+		all.Holdings = append(all.Holdings, next.Holdings...)
+	}
+
+	all.Page, all.PageSize = 1, len(all.Holdings)
+	return all, nil
+}
+
+// Login logs in with the configured credentials. It is called automatically
+// when needed, calling it upfront reports wrong credentials early.
+func (c *Client) Login(ctx context.Context) error {
+	if c.config.APIKey != "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.login(ctx)
+}
+
+// Logout revokes the session of the client. The next request logs in again.
+func (c *Client) Logout(ctx context.Context) error {
+	if c.config.APIKey != "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	tokens := c.tokens
+	c.tokens = nil
+	c.mu.Unlock()
+
+	if tokens == nil {
+		return nil
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, "/logout", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+
+	return c.send(req, nil)
+}
+
+// do sends an authenticated request and decodes the JSON response into out.
+// Requests rejected with 401 are sent once more with new tokens, e.g. when
+// the session was revoked in between.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		authorization, err := c.authorization(ctx, attempt > 0)
+		if err != nil {
+			return err
+		}
+
+		err = c.retry(ctx, method, func() (*http.Request, error) {
+			req, err := c.newRequest(ctx, method, path, data)
+			if err != nil {
+				return nil, err
+			}
+			c.authorize(req, authorization)
+			return req, nil
+		}, out)
+
+		if attempt == 0 && c.config.APIKey == "" && errors.Is(err, ErrUnauthorized) {
+			continue
+		}
+		return err
+	}
+}
+
+// authorization returns the Authorization header value of the next request.
+// The access token is refreshed when it is about to expire or when force is set.
+func (c *Client) authorization(ctx context.Context, force bool) (string, error) {
+	if c.config.APIKey != "" {
+		return "", nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case c.tokens == nil:
+		if err := c.login(ctx); err != nil {
+			return "", err
+		}
+	case force || time.Until(c.tokens.ExpiresAt) < tokenExpiryMargin:
+		if err := c.refresh(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return "Bearer " + c.tokens.AccessToken, nil
+}
+
+func (c *Client) authorize(req *http.Request, authorization string) {
+	if c.config.APIKey != "" {
+		req.Header.Set("X-API-Key", c.config.APIKey)
+		return
+	}
+	req.Header.Set("Authorization", authorization)
+}
+
+// login gets new tokens with the credentials, c.mu has to be held.
+func (c *Client) login(ctx context.Context) error {
+	var tokens models.TokenResponse
+	err := c.post(ctx, "/login", models.LoginRequest{
+		Username: c.config.Username,
+		Password: c.config.Password,
+	}, &tokens)
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+
+	c.tokens = &tokens
+	return nil
+}
+
+// refresh exchanges the refresh token for new tokens, c.mu has to be held.
+// When the refresh token is no longer valid, it logs in again.
+func (c *Client) refresh(ctx context.Context) error {
+	var tokens models.TokenResponse
+	err := c.post(ctx, "/token/refresh", models.RefreshRequest{RefreshToken: c.tokens.RefreshToken}, &tokens)
+	if errors.Is(err, ErrUnauthorized) {
+		c.tokens = nil
+		return c.login(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("token refresh failed: %w", err)
+	}
+
+	c.tokens = &tokens
+	return nil
+}
+
+// post sends an unauthenticated JSON request.
+func (c *Client) post(ctx context.Context, path string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	return c.retry(ctx, http.MethodPost, func() (*http.Request, error) {
+		return c.newRequest(ctx, http.MethodPost, path, data)
+	}, out)
+}
+
+// retry sends the request built by newRequest until it succeeds, fails with
+// an error that is not worth retrying or the retries are used up. Only GET
+// requests are retried after network errors and 5xx responses, since others
+// may have been processed. 429 responses are retried for every method.
+func (c *Client) retry(ctx context.Context, method string, newRequest func() (*http.Request, error), out interface{}) error {
+	delay := c.config.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return err
+		}
+
+		err = c.send(req, out)
+		if err == nil || attempt >= c.config.MaxRetries {
+			return err
+		}
+
+		wait, ok := retryDelay(err, method, delay)
+		if !ok {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		delay *= 2
+	}
+}
+
+// retryDelay reports whether a failed request should be retried and how long to wait before.
+func retryDelay(err error, method string, backoff time.Duration) (time.Duration, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		// Network errors, but not cancellations by the caller
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+		return jitter(backoff), method == http.MethodGet
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests:
+		if apiErr.RetryAfter > 0 {
+			return minDuration(apiErr.RetryAfter, maxRetryDelay), true
+		}
+		return jitter(backoff), true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return jitter(backoff), method == http.MethodGet
+	default:
+		return 0, false
+	}
+}
+
+// jitter spreads retries of many clients by up to a quarter of the delay.
+func jitter(d time.Duration) time.Duration {
+	d = minDuration(d, maxRetryDelay)
+	return d + time.Duration(rand.Int63n(int64(d)/4+1))
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL.String()+path, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// send sends the request once and decodes a successful JSON response into out.
+func (c *Client) send(req *http.Request, out interface{}) error {
+	resp, err := c.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(resp)
+	}
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("could not decode the response of %s %s: %w", req.Method, req.URL.Path, err)
+	}
+
+	return nil
+}
+
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return apiErr
+}
@@ -0,0 +1,281 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"awesomeProject/internal"
+	"awesomeProject/models"
+)
+
+const (
+	testUsername = "reporting"
+	testPassword = "correct horse battery staple"
+
+	// testHoldings is the number of positions of the SPY holdings
+	testHoldings = 5
+)
+
+// testAPI is the API built by internal.MakeHTTPHandler on a memory store.
+type testAPI struct {
+	*httptest.Server
+	server *internal.Server
+}
+
+// unlimited keeps the rate limiter out of tests that do not test it.
+var unlimited = internal.RateLimit{Rate: 1000, Burst: 1000}
+
+// newTestAPI starts the API with SPY, whose full holdings are stored, QQQ
+// without holdings and a viewer named testUsername. viewerLimit is the rate
+// limit of viewers.
+func newTestAPI(t *testing.T, viewerLimit internal.RateLimit) *testAPI {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	store := internal.NewMemoryStore()
+	err := store.UpsertMany([]models.ETF{
+		{ID: "SPY", Data: models.ETFData{Name: "SPY", Description: "The S&P 500 Index"}.ToJson()},
+		{ID: "QQQ", Data: models.ETFData{Name: "QQQ", Description: "The Nasdaq-100 Index"}.ToJson()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	holdings := models.FullHoldings{Ticker: "SPY", AsOf: "2026-10-16"}
+	for i := 0; i < testHoldings; i++ {
+		holdings.Holdings = append(holdings.Holdings, models.FullHolding{Name: fmt.Sprintf("Holding %d", i)})
+	}
+	if err := store.ReplaceHoldings([]models.FullHoldings{holdings}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := internal.NewServer(logger, store, nil)
+	t.Cleanup(server.Close)
+
+	if _, err := server.CreateUser(models.CreateUserRequest{Username: testUsername, Password: testPassword, Role: models.RoleViewer}); err != nil {
+		t.Fatal(err)
+	}
+
+	limits := internal.RateLimitConfig{
+		Roles: map[models.Role]internal.RateLimit{
+			models.RoleViewer:  viewerLimit,
+			models.RoleAnalyst: unlimited,
+			models.RoleAdmin:   unlimited,
+		},
+		Anonymous: unlimited,
+	}
+
+	handlers := internal.NewHandler(server,
+		internal.NewHMACKeySet([]byte("test secret that is only used by the tests")),
+		internal.NewRateLimiter(limits, store, logger),
+		internal.NewLoginThrottle(internal.DefaultLoginThrottleConfig(), logger),
+		internal.NewHealthChecker(store, nil, time.Hour, logger))
+
+	handler, err := internal.MakeHTTPHandler(handlers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := &testAPI{Server: httptest.NewServer(handler), server: server}
+	t.Cleanup(api.Close)
+
+	return api
+}
+
+func (a *testAPI) client(t *testing.T, config Config) *Client {
+	t.Helper()
+
+	if config.APIKey == "" && config.Username == "" {
+		config.Username, config.Password = testUsername, testPassword
+	}
+	config.RetryBackoff = time.Millisecond
+
+	c, err := New(a.URL, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestLoginAndRefresh(t *testing.T) {
+	api := newTestAPI(t, unlimited)
+	c := api.client(t, Config{})
+	ctx := context.Background()
+
+	if err := c.Login(ctx); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	login := *c.tokens
+
+	tickers, err := c.ListETFs(ctx)
+	if err != nil {
+		t.Fatalf("ListETFs: %v", err)
+	}
+	if len(tickers) != 2 {
+		t.Errorf("tickers = %v", tickers)
+	}
+	if c.tokens.RefreshToken != login.RefreshToken {
+		t.Error("a valid access token was refreshed")
+	}
+
+	// An access token about to expire is refreshed before the request
+	c.tokens.ExpiresAt = time.Now()
+	if _, err := c.GetETF(ctx, "SPY"); err != nil {
+		t.Fatalf("GetETF with an expiring access token: %v", err)
+	}
+	if c.tokens.RefreshToken == login.RefreshToken {
+		t.Error("the expiring access token was not refreshed")
+	}
+
+	// A revoked session fails the refresh, so the client logs in again
+	if err := api.server.RevokeAllSessions(testUsername); err != nil {
+		t.Fatal(err)
+	}
+	refreshed := *c.tokens
+	if _, err := c.GetETF(ctx, "SPY"); err != nil {
+		t.Fatalf("GetETF after the session was revoked: %v", err)
+	}
+	if c.tokens.AccessToken == refreshed.AccessToken {
+		t.Error("the client did not log in again")
+	}
+
+	if err := c.Logout(ctx); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := c.ListETFs(ctx); err != nil {
+		t.Fatalf("ListETFs after Logout: %v", err)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	api := newTestAPI(t, unlimited)
+
+	key, err := api.server.CreateAPIKey(testUsername, models.CreateAPIKeyRequest{Name: "reports"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := api.client(t, Config{APIKey: key.Key})
+	if _, err := c.GetETF(context.Background(), "SPY"); err != nil {
+		t.Fatalf("GetETF: %v", err)
+	}
+	if c.tokens != nil {
+		t.Error("a client with an API key logged in")
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	api := newTestAPI(t, unlimited)
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := api.client(t, Config{}).GetETF(ctx, "NONE")
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("GetETF of an unknown ETF = %v, want ErrNotFound", err)
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 || apiErr.RequestID == "" {
+			t.Errorf("APIError = %+v, want status 404 and a request ID", apiErr)
+		}
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		c := api.client(t, Config{Username: testUsername, Password: "wrong password"})
+
+		if err := c.Login(ctx); !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("Login = %v, want ErrUnauthorized", err)
+		}
+		if _, err := c.ListETFs(ctx); !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("ListETFs = %v, want ErrUnauthorized", err)
+		}
+	})
+
+	t.Run("unknown API key", func(t *testing.T) {
+		_, err := api.client(t, Config{APIKey: "unknown"}).ListETFs(ctx)
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("ListETFs = %v, want ErrUnauthorized", err)
+		}
+	})
+}
+
+func TestRateLimited(t *testing.T) {
+	// A single request, the next token would take far longer than the test
+	api := newTestAPI(t, internal.RateLimit{Rate: 0.001, Burst: 1})
+	ctx := context.Background()
+
+	c := api.client(t, Config{MaxRetries: -1})
+	if _, err := c.ListETFs(ctx); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	_, err := c.ListETFs(ctx)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("second request = %v, want ErrRateLimited", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter <= 0 {
+		t.Errorf("APIError = %+v, want the Retry-After of the server", apiErr)
+	}
+
+	// The retries wait for the Retry-After of the server, until the context ends
+	c = api.client(t, Config{})
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, err := c.ListETFs(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("retried request = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestHoldingsPagination(t *testing.T) {
+	api := newTestAPI(t, unlimited)
+	c := api.client(t, Config{})
+	ctx := context.Background()
+
+	page, err := c.ListHoldings(ctx, "SPY", 2, 2)
+	if err != nil {
+		t.Fatalf("ListHoldings: %v", err)
+	}
+	if page.Page != 2 || page.PageSize != 2 || page.Total != testHoldings || page.AsOf != "2026-10-16" ||
+		len(page.Holdings) != 2 || page.Holdings[0].Name != "Holding 2" {
+		t.Errorf("second page = %+v", page)
+	}
+
+	page, err = c.ListHoldings(ctx, "SPY", 0, 0)
+	if err != nil {
+		t.Fatalf("ListHoldings with the defaults: %v", err)
+	}
+	if page.Page != 1 || len(page.Holdings) != testHoldings {
+		t.Errorf("default page = %+v", page)
+	}
+
+	all, err := c.AllHoldings(ctx, "SPY", 2)
+	if err != nil {
+		t.Fatalf("AllHoldings: %v", err)
+	}
+	if all.Total != testHoldings || len(all.Holdings) != testHoldings {
+		t.Fatalf("AllHoldings = %+v", all)
+	}
+	for i, holding := range all.Holdings {
+		if want := fmt.Sprintf("Holding %d", i); holding.Name != want {
+			t.Errorf("position %d = %q, want %q", i, holding.Name, want)
+		}
+	}
+
+	if _, err := c.ListHoldings(ctx, "QQQ", 1, 10); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ListHoldings without holdings = %v, want ErrNotFound", err)
+	}
+	if _, err := c.ListHoldings(ctx, "SPY", 1, 100000); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("ListHoldings with a too large page = %v, want ErrBadRequest", err)
+	}
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrBadRequest matches APIErrors with status 400.
+	ErrBadRequest = errors.New("bad request")
+	// ErrUnauthorized matches APIErrors with status 401, the credentials or tokens are not valid.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches APIErrors with status 403, e.g. when the role of the user is not sufficient.
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound matches APIErrors with status 404.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited matches APIErrors with status 429 that are left after retrying.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer matches APIErrors with a 5xx status.
+	ErrServer = errors.New("server error")
+)
+
+// APIError is returned when the API responds with an error status.
+// Use errors.Is with the Err variables to check for specific errors.
+type APIError struct {
+	StatusCode int
+	// Message is the error message sent by the server, it is often empty.
+	Message string
+	// RequestID identifies the request in the server logs and the audit log.
+	RequestID string
+	// RetryAfter is how long the server asked to wait before the next request.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("etf api: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += " (request " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// Unwrap returns the Err variable matching the status code, or nil.
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrServer
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+)
+
+func exportCommand(a *app, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := flags.String("out", "", "write the JSON to `FILE` instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		return errors.New("export takes no arguments")
+	}
+
+	server, err := a.serverInstance()
+	if err != nil {
+		return err
+	}
+
+	etfs, err := server.ExportETFs()
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return printJSON(a.out, etfs)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+
+	if err := printJSON(f, etfs); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func getCommand(a *app, args []string) error {
+	if len(args) != 1 {
+		return errors.New("expected exactly one ticker")
+	}
+
+	server, err := a.serverInstance()
+	if err != nil {
+		return err
+	}
+
+	etf, err := server.GetETF(args[0])
+	if err != nil {
+		return err
+	}
+
+	return printJSON(a.out, etf)
+}
@@ -0,0 +1,132 @@
+// Command etfctl runs single operator tasks against the database of the ETF
+// server: scraping, migrations, user management and data export. It reads
+// the same environment variables as the server, see internal.LoadConfig.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"awesomeProject/internal"
+)
+
+const usage = `Usage: etfctl <command> [arguments]
+
+Commands:
+  scrape --once [--atomic]      scrape every ETF and store the data
+  scrape --url URL | --file F   parse one fund page and print the data without storing it
+  scrape --holdings F           parse a saved holdings spreadsheet and print the positions
+  migrate up                    apply all pending migrations
+  migrate down [N]              revert the last N migrations, 1 by default
+  migrate goto VERSION          apply or revert migrations up to VERSION
+  migrate force VERSION         mark VERSION as applied and clear the dirty flag
+  migrate version               print the current migration version and dirty state
+  user add [flags] USERNAME     create a user
+  user passwd [flags] USERNAME  set the password of a user
+  user disable USERNAME         disable a user and revoke their sessions
+  export [--out FILE]           print the data of all ETFs as JSON
+  get TICKER                    print the data of one ETF as JSON
+
+Run etfctl <command> -h for the flags of a command.
+`
+
+// command is the signature of the subcommands, args excludes the command name.
+type command func(app *app, args []string) error
+
+var commands = map[string]command{
+	"scrape":  scrapeCommand,
+	"migrate": migrateCommand,
+	"user":    userCommand,
+	"export":  exportCommand,
+	"get":     getCommand,
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "etfctl: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	a := newApp()
+	defer a.close()
+
+	if err := cmd(a, os.Args[2:]); err != nil {
+		a.close()
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "etfctl %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// app holds what the commands share. The database and server are only
+// created when a command needs them, so e.g. parsing a file works offline.
+type app struct {
+	config internal.Config
+	logger *logrus.Logger
+	out    io.Writer
+
+	store  internal.Store
+	server *internal.Server
+}
+
+func newApp() *app {
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+
+	return &app{
+		config: internal.LoadConfig(),
+		logger: logger,
+		out:    os.Stdout,
+	}
+}
+
+func (a *app) database() (internal.Store, error) {
+	if a.store == nil {
+		store, err := a.config.OpenStore()
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to the database: %w", err)
+		}
+		a.store = store
+	}
+	return a.store, nil
+}
+
+func (a *app) serverInstance() (*internal.Server, error) {
+	if a.server == nil {
+		store, err := a.database()
+		if err != nil {
+			return nil, err
+		}
+		a.server = internal.NewServer(a.logger, store, nil)
+	}
+	return a.server, nil
+}
+
+// close writes pending audit events.
+func (a *app) close() {
+	if a.server != nil {
+		a.server.Close()
+		a.server = nil
+	}
+}
+
+// printJSON writes v as indented JSON to w.
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"awesomeProject/internal"
+)
+
+func migrateCommand(a *app, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected up, down, goto, force or version")
+	}
+
+	store, err := a.database()
+	if err != nil {
+		return err
+	}
+
+	migrator, ok := store.(internal.Migrator)
+	if !ok {
+		return fmt.Errorf("the %s store has no migrations", a.config.DBDriver)
+	}
+
+	switch args[0] {
+	case "up":
+		if len(args) != 1 {
+			return errors.New("up takes no arguments")
+		}
+		if err := migrator.RunMigrations(); err != nil {
+			return err
+		}
+	case "down":
+		steps := 1
+		if len(args) > 2 {
+			return errors.New("down takes at most one argument")
+		}
+		if len(args) == 2 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of migrations %q", args[1])
+			}
+		}
+		if err := migrator.RollbackMigrations(steps); err != nil {
+			return err
+		}
+	case "goto":
+		if len(args) != 2 {
+			return errors.New("goto takes exactly one version")
+		}
+		version, err := strconv.ParseUint(args[1], 10, 0)
+		if err != nil || version < 1 {
+			return fmt.Errorf("invalid version %q, use down to revert every migration", args[1])
+		}
+		if err := migrator.MigrateTo(uint(version)); err != nil {
+			return err
+		}
+	case "force":
+		if len(args) != 2 {
+			return errors.New("force takes exactly one version")
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil || version < -1 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err := migrator.ForceMigrationVersion(version); err != nil {
+			return err
+		}
+	case "version":
+		if len(args) != 1 {
+			return errors.New("version takes no arguments")
+		}
+	default:
+		return fmt.Errorf("unknown subcommand %q, expected up, down, goto, force or version", args[0])
+	}
+
+	version, dirty, err := migrator.MigrationVersion()
+	if err != nil {
+		return err
+	}
+
+	latest, err := migrator.LatestMigrationVersion()
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		_, err = fmt.Fprintf(a.out, "version %d of %d (dirty, fix the database and run migrate force)\n", version, latest)
+		return err
+	}
+
+	_, err = fmt.Fprintf(a.out, "version %d of %d\n", version, latest)
+	return err
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"awesomeProject/internal"
+	"awesomeProject/models"
+)
+
+func scrapeCommand(a *app, args []string) error {
+	flags := flag.NewFlagSet("scrape", flag.ContinueOnError)
+	once := flags.Bool("once", false, "scrape every ETF once and store the data")
+	atomic := flags.Bool("atomic", false, "with --once, store all ETFs in one transaction or none at all")
+	url := flags.String("url", "", "parse the fund page at `URL` and print the data without storing it")
+	file := flags.String("file", "", "parse the fund page saved in `FILE` and print the data without storing it")
+	holdings := flags.String("holdings", "", "parse the holdings spreadsheet saved in `FILE` and print the positions without storing them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	set := 0
+	for _, given := range []bool{*once, *url != "", *file != "", *holdings != ""} {
+		if given {
+			set++
+		}
+	}
+	if set != 1 || flags.NArg() > 0 {
+		return errors.New("exactly one of --once, --url, --file and --holdings is required")
+	}
+
+	if !*once {
+		// Dry runs need neither the database nor the fund finder
+		updater := internal.NewDailyDataUpdater(a.config.SourceHost, nil, nil, a.config.UpdaterConfig(), a.logger)
+
+		if *holdings != "" {
+			positions, err := parseHoldingsFile(updater, *holdings)
+			if err != nil {
+				return err
+			}
+
+			return printJSON(a.out, positions)
+		}
+
+		var (
+			etf *models.ETFData
+			err error
+		)
+		if *url != "" {
+			etf, err = updater.FetchETF(*url)
+		} else {
+			etf, err = parseFile(updater, *file)
+		}
+		if err != nil {
+			return err
+		}
+
+		return printJSON(a.out, etf)
+	}
+
+	store, err := a.database()
+	if err != nil {
+		return err
+	}
+
+	config := a.config.UpdaterConfig()
+	if *atomic {
+		config.Atomic = true
+	}
+
+	updater := internal.NewDailyDataUpdater(a.config.SourceHost, store, nil, config, a.logger)
+	if err := updater.UpdateData(); err != nil {
+		return err
+	}
+
+	a.logger.Info("Data update completed successfully")
+	return nil
+}
+
+func parseFile(updater *internal.DailyDataUpdater, path string) (*models.ETFData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return updater.ParseETF(f)
+}
+
+func parseHoldingsFile(updater *internal.DailyDataUpdater, path string) (*models.FullHoldings, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// The files are named holdings-daily-us-en-<ticker>.xlsx
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	ticker := strings.ToUpper(name[strings.LastIndex(name, "-")+1:])
+
+	return updater.ParseHoldings(f, ticker)
+}
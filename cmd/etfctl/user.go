@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	osuser "os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"awesomeProject/internal"
+	"awesomeProject/models"
+)
+
+func userCommand(a *app, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected add, passwd or disable")
+	}
+
+	switch args[0] {
+	case "add":
+		return userAdd(a, args[1:])
+	case "passwd":
+		return userPasswd(a, args[1:])
+	case "disable":
+		return userDisable(a, args[1:])
+	default:
+		return fmt.Errorf("unknown subcommand %q, expected add, passwd or disable", args[0])
+	}
+}
+
+func userAdd(a *app, args []string) error {
+	flags := flag.NewFlagSet("user add", flag.ContinueOnError)
+	email := flags.String("email", "", "email address of the user")
+	role := flags.String("role", string(models.RoleViewer), "role of the user: viewer, analyst or admin")
+	password := flags.String("password", "", "password of the user, read from stdin when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	username, err := usernameArg(flags)
+	if err != nil {
+		return err
+	}
+
+	if *password == "" {
+		if *password, err = readPassword(); err != nil {
+			return err
+		}
+	}
+
+	server, err := a.serverInstance()
+	if err != nil {
+		return err
+	}
+
+	user, err := server.CreateUser(models.CreateUserRequest{
+		Username: username,
+		Password: *password,
+		Email:    *email,
+		Role:     models.Role(*role),
+	})
+	if err != nil {
+		return err
+	}
+
+	recordAudit(server, "user add", user.ID)
+
+	_, err = fmt.Fprintf(a.out, "created user %s (id %d, role %s)\n", user.Username, user.ID, user.Role)
+	return err
+}
+
+func userPasswd(a *app, args []string) error {
+	flags := flag.NewFlagSet("user passwd", flag.ContinueOnError)
+	password := flags.String("password", "", "new password of the user, read from stdin when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	username, err := usernameArg(flags)
+	if err != nil {
+		return err
+	}
+
+	server, err := a.serverInstance()
+	if err != nil {
+		return err
+	}
+
+	user, err := server.GetUser(username)
+	if err != nil {
+		return err
+	}
+
+	if *password == "" {
+		if *password, err = readPassword(); err != nil {
+			return err
+		}
+	}
+
+	if err := server.ResetPassword(user.ID, *password); err != nil {
+		return err
+	}
+
+	recordAudit(server, "user passwd", user.ID)
+
+	_, err = fmt.Fprintf(a.out, "password of %s changed, all sessions revoked\n", user.Username)
+	return err
+}
+
+func userDisable(a *app, args []string) error {
+	flags := flag.NewFlagSet("user disable", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	username, err := usernameArg(flags)
+	if err != nil {
+		return err
+	}
+
+	server, err := a.serverInstance()
+	if err != nil {
+		return err
+	}
+
+	user, err := server.GetUser(username)
+	if err != nil {
+		return err
+	}
+
+	if err := server.SetUserDisabled(user.ID, true); err != nil {
+		return err
+	}
+
+	recordAudit(server, "user disable", user.ID)
+
+	_, err = fmt.Fprintf(a.out, "disabled %s, all sessions revoked\n", user.Username)
+	return err
+}
+
+func usernameArg(flags *flag.FlagSet) (string, error) {
+	if flags.NArg() != 1 {
+		return "", errors.New("expected exactly one username")
+	}
+	return flags.Arg(0), nil
+}
+
+// readPassword reads the password from the first line of stdin, so it does
+// not end up in the shell history.
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("could not read the password: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// recordAudit records user changes made with etfctl like the ones made through the admin API.
+func recordAudit(server *internal.Server, action string, userID int) {
+	details := map[string]string{"via": "etfctl"}
+	if u, err := osuser.Current(); err == nil {
+		details["os_user"] = u.Username
+	}
+
+	server.RecordAudit(models.AuditEvent{
+		OccurredAt: time.Now(),
+		Type:       models.AuditAdminAction,
+		Method:     "CLI",
+		Path:       action,
+		Resource:   "user:" + strconv.Itoa(userID),
+		Details:    details,
+	})
+}
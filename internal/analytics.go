@@ -0,0 +1,251 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"awesomeProject/models"
+)
+
+const (
+	// maxAnalyticsTickers bounds the number of ETFs compared or combined at once.
+	maxAnalyticsTickers = 20
+)
+
+// Overlap returns the top holdings all of the ETFs have in common. Holdings
+// are matched by name, ignoring case, since the fund pages show no identifiers.
+func (s Server) Overlap(tickers []string) (*models.Overlap, error) {
+	if len(tickers) < 2 || len(tickers) > maxAnalyticsTickers {
+		return nil, fmt.Errorf("%w: between 2 and %d tickers are required", ErrInvalidInput, maxAnalyticsTickers)
+	}
+
+	overlap := &models.Overlap{Tickers: tickers, Holdings: []models.OverlapHolding{}}
+
+	// common maps the normalized holding name to the holding of every ETF seen so far
+	var common map[string]*models.OverlapHolding
+	for i, ticker := range tickers {
+		etf, err := s.GetETF(ticker)
+		if err != nil {
+			return nil, err
+		}
+
+		weights := holdingWeights(etf.TopHoldings)
+
+		if i == 0 {
+			common = map[string]*models.OverlapHolding{}
+			for key, w := range weights {
+				common[key] = &models.OverlapHolding{
+					Name:    w.name,
+					Weight:  w.weight,
+					Weights: map[string]float64{ticker: w.weight},
+				}
+			}
+			continue
+		}
+
+		for key, holding := range common {
+			w, ok := weights[key]
+			if !ok {
+				delete(common, key)
+				continue
+			}
+
+			holding.Weights[ticker] = w.weight
+			if w.weight < holding.Weight {
+				holding.Weight = w.weight
+			}
+		}
+	}
+
+	for _, holding := range common {
+		overlap.Weight += holding.Weight
+		overlap.Holdings = append(overlap.Holdings, *holding)
+	}
+
+	sort.Slice(overlap.Holdings, func(i, j int) bool {
+		if overlap.Holdings[i].Weight != overlap.Holdings[j].Weight {
+			return overlap.Holdings[i].Weight > overlap.Holdings[j].Weight
+		}
+		return overlap.Holdings[i].Name < overlap.Holdings[j].Name
+	})
+
+	return overlap, nil
+}
+
+// Tracking compares the top holdings of an ETF with those of its benchmark
+// index, matched by name like in Overlap. A holding only one of the lists
+// shows may still be held below the top holdings of the other, so its weight
+// there is unknown and it does not add to the deviation.
+func (s Server) Tracking(ticker string) (*models.Tracking, error) {
+	etf, err := s.GetETF(ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(etf.IndexTopHoldings) == 0 {
+		return nil, fmt.Errorf("index top holdings %w", ErrNotFound)
+	}
+
+	tracking := &models.Tracking{Ticker: etf.Name, Holdings: []models.TrackingHolding{}}
+	if etf.Characteristics != nil {
+		tracking.Benchmark = etf.Characteristics.Benchmark
+	}
+
+	fund := holdingWeights(etf.TopHoldings)
+	index := holdingWeights(etf.IndexTopHoldings)
+
+	for key, f := range fund {
+		fundWeight := f.weight
+		holding := models.TrackingHolding{Name: f.name, FundWeight: &fundWeight}
+		tracking.FundWeight += fundWeight
+
+		if i, ok := index[key]; ok {
+			indexWeight := i.weight
+			difference := fundWeight - indexWeight
+			holding.IndexWeight = &indexWeight
+			holding.Difference = &difference
+			tracking.Deviation += math.Abs(difference)
+		}
+
+		tracking.Holdings = append(tracking.Holdings, holding)
+	}
+
+	for key, i := range index {
+		indexWeight := i.weight
+		tracking.IndexWeight += indexWeight
+
+		if _, ok := fund[key]; !ok {
+			tracking.Holdings = append(tracking.Holdings, models.TrackingHolding{Name: i.name, IndexWeight: &indexWeight})
+		}
+	}
+
+	// The largest deviations first, then the holdings only one of the lists shows by weight
+	sort.Slice(tracking.Holdings, func(i, j int) bool {
+		a, b := tracking.Holdings[i], tracking.Holdings[j]
+		if (a.Difference != nil) != (b.Difference != nil) {
+			return a.Difference != nil
+		}
+		if a.Difference != nil && math.Abs(*a.Difference) != math.Abs(*b.Difference) {
+			return math.Abs(*a.Difference) > math.Abs(*b.Difference)
+		}
+		if trackingWeight(a) != trackingWeight(b) {
+			return trackingWeight(a) > trackingWeight(b)
+		}
+		return a.Name < b.Name
+	})
+
+	return tracking, nil
+}
+
+// trackingWeight is the larger known weight of a holding.
+func trackingWeight(holding models.TrackingHolding) float64 {
+	var weight float64
+	if holding.FundWeight != nil {
+		weight = *holding.FundWeight
+	}
+	if holding.IndexWeight != nil && *holding.IndexWeight > weight {
+		weight = *holding.IndexWeight
+	}
+	return weight
+}
+
+// Exposure combines the top holdings, sectors and countries of the ETFs of a
+// portfolio weighted by their share of it. The weights of the positions are
+// normalized to a sum of 100. Holdings only cover the top holdings of each
+// ETF, so they add up to less than 100.
+func (s Server) Exposure(positions []models.Position) (*models.Exposure, error) {
+	if len(positions) == 0 || len(positions) > maxAnalyticsTickers {
+		return nil, fmt.Errorf("%w: between 1 and %d positions are required", ErrInvalidInput, maxAnalyticsTickers)
+	}
+
+	var total float64
+	for _, position := range positions {
+		if position.Ticker == "" || position.Weight <= 0 {
+			return nil, fmt.Errorf("%w: every position needs a ticker and a positive weight", ErrInvalidInput)
+		}
+		total += position.Weight
+	}
+
+	holdings := exposureSum{}
+	sectors := exposureSum{}
+	countries := exposureSum{}
+
+	for _, position := range positions {
+		etf, err := s.GetETF(position.Ticker)
+		if err != nil {
+			return nil, err
+		}
+
+		share := position.Weight / total
+
+		for key, w := range holdingWeights(etf.TopHoldings) {
+			holdings.add(key, w.name, w.weight*share)
+		}
+		for _, sector := range etf.Sectors {
+			sectors.add(normalizeName(sector.Name), sector.Name, parseWeight(sector.Weight)*share)
+		}
+		for _, country := range etf.Countries {
+			countries.add(normalizeName(country.Name), country.Name, parseWeight(country.Weight)*share)
+		}
+	}
+
+	return &models.Exposure{
+		Holdings:  holdings.sorted(),
+		Sectors:   sectors.sorted(),
+		Countries: countries.sorted(),
+	}, nil
+}
+
+type namedWeight struct {
+	name   string
+	weight float64
+}
+
+// holdingWeights sums the weights of the holdings by normalized name, a fund
+// may list several share classes of one company.
+func holdingWeights(holdings []models.Holding) map[string]namedWeight {
+	weights := map[string]namedWeight{}
+	for _, holding := range holdings {
+		key := normalizeName(holding.Name)
+		w, ok := weights[key]
+		if !ok {
+			w.name = strings.TrimSpace(holding.Name)
+		}
+		w.weight += parseWeight(holding.Weight)
+		weights[key] = w
+	}
+	return weights
+}
+
+// exposureSum adds up weights by normalized name.
+type exposureSum map[string]*namedWeight
+
+func (e exposureSum) add(key, name string, weight float64) {
+	if w, ok := e[key]; ok {
+		w.weight += weight
+		return
+	}
+	e[key] = &namedWeight{name: strings.TrimSpace(name), weight: weight}
+}
+
+func (e exposureSum) sorted() []models.ExposureWeight {
+	result := make([]models.ExposureWeight, 0, len(e))
+	for _, w := range e {
+		result = append(result, models.ExposureWeight{Name: w.name, Weight: w.weight})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Weight != result[j].Weight {
+			return result[i].Weight > result[j].Weight
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,218 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"awesomeProject/models"
+)
+
+const (
+	// auditBufferSize is how many events may wait to be written in the
+	// background. Once it is full, events are written while the request waits.
+	auditBufferSize = 1024
+
+	// requestIDHeader carries the request ID from and to the client.
+	requestIDHeader = "X-Request-ID"
+
+	// maxRequestIDLength limits request IDs taken over from clients.
+	maxRequestIDLength = 64
+
+	// requestIDContextKey stores the request ID in the request context.
+	requestIDContextKey contextKey = "request_id"
+
+	// auditContextKey stores the audit event of the current request in the request context.
+	auditContextKey contextKey = "audit_event"
+)
+
+// AuditStore persists audit events.
+type AuditStore interface {
+	InsertAuditEvent(event models.AuditEvent) error
+}
+
+// Auditor writes audit events in the background so requests do not wait for
+// the database. No event is dropped: when the background writer falls behind
+// or the Auditor was closed, events are written synchronously instead.
+type Auditor struct {
+	store  AuditStore
+	logger *logrus.Logger
+	events chan models.AuditEvent
+	done   chan struct{}
+
+	// mu guards closed, Record holds it for reading while it may send to events
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAuditor creates an Auditor and starts writing events.
+func NewAuditor(store AuditStore, logger *logrus.Logger) *Auditor {
+	a := &Auditor{
+		store:  store,
+		logger: logger,
+		events: make(chan models.AuditEvent, auditBufferSize),
+		done:   make(chan struct{}),
+	}
+
+	go a.run()
+
+	return a
+}
+
+// Record queues an event for writing. When the buffer is full because the
+// database cannot keep up, the event is written before Record returns, which
+// slows down the request instead of losing the event.
+func (a *Auditor) Record(event models.AuditEvent) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if !a.closed {
+		select {
+		case a.events <- event:
+			return
+		default:
+		}
+	}
+
+	a.write(event)
+}
+
+// Close writes the queued events and stops the background writer. Events
+// recorded afterwards are written synchronously.
+func (a *Auditor) Close() {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.events)
+	}
+	a.mu.Unlock()
+
+	<-a.done
+}
+
+func (a *Auditor) run() {
+	defer close(a.done)
+
+	for event := range a.events {
+		a.write(event)
+	}
+}
+
+func (a *Auditor) write(event models.AuditEvent) {
+	if err := a.store.InsertAuditEvent(event); err != nil {
+		a.logger.Errorf("Could not write %s audit event of %s: %v", event.Type, event.Username, err)
+	}
+}
+
+// RequestID middleware function that assigns every request an ID. A valid
+// X-Request-ID sent by the client is kept so requests can be traced across services.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			var err error
+			if id, err = randomID(8); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey, id)))
+	})
+}
+
+// Audit returns a middleware that records one audit event per authenticated
+// request. Handlers can change the type and resource of the event with
+// annotateAudit. It must run after RequireTokenAuthentication.
+func (h Handlers) Audit(eventType models.AuditEventType) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			event := h.newAuditEvent(r, eventType)
+			if p := principalFromContext(r.Context()); p != nil {
+				event.Details = authDetails(p)
+			}
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), auditContextKey, &event)))
+
+			event.Status = sw.status
+			h.server.RecordAudit(event)
+		})
+	}
+}
+
+// audit records an event outside of the Audit middleware, e.g. for logins.
+func (h Handlers) audit(r *http.Request, eventType models.AuditEventType, username string, status int, details map[string]string) {
+	event := h.newAuditEvent(r, eventType)
+	event.Username = username
+	event.Status = status
+	event.Details = details
+	h.server.RecordAudit(event)
+}
+
+func (h Handlers) newAuditEvent(r *http.Request, eventType models.AuditEventType) models.AuditEvent {
+	return models.AuditEvent{
+		OccurredAt: time.Now(),
+		Type:       eventType,
+		Username:   usernameFromContext(r.Context()),
+		IP:         clientIP(r),
+		RequestID:  requestIDFromContext(r.Context()),
+		Method:     r.Method,
+		Path:       r.URL.Path,
+	}
+}
+
+// annotateAudit sets the type and resource of the audit event of the request.
+func annotateAudit(ctx context.Context, eventType models.AuditEventType, resource string) {
+	if event, ok := ctx.Value(auditContextKey).(*models.AuditEvent); ok {
+		event.Type = eventType
+		event.Resource = resource
+	}
+}
+
+// authDetails describes how the principal authenticated.
+func authDetails(p *principal) map[string]string {
+	if p.APIKeyID != 0 {
+		return map[string]string{"auth": "api_key", "api_key_id": strconv.Itoa(p.APIKeyID)}
+	}
+	return map[string]string{"auth": "jwt", "session_id": p.SessionID}
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// statusWriter remembers the status code written by the handler.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
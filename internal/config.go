@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"awesomeProject/models"
+)
+
+// Config holds the settings shared by the server and etfctl. Every setting
+// can be overridden with the environment variable named in its comment.
+type Config struct {
+	// DBDriver selects the store: postgres, sqlite or memory, DB_DRIVER.
+	// The memory store loses everything on restart, it is meant for trying things out.
+	DBDriver string
+
+	// DBPath is the SQLite database file, DB_PATH.
+	DBPath string
+
+	// DBHost, DBPort, DBUser, DBPassword and DBName locate the PostgreSQL database,
+	// DB_HOST, DB_PORT, DB_USER, DB_PASSWORD and DB_NAME.
+	DBHost     string
+	DBPort     string
+	DBUser     string
+	DBPassword string
+	DBName     string
+
+	// AutoMigrate applies pending migrations when the server starts, AUTO_MIGRATE.
+	// Turn it off to apply them with etfctl migrate before a deployment instead.
+	AutoMigrate bool
+
+	// ServerAddr is the address of the REST API, SERVER_ADDR.
+	ServerAddr string
+	// GRPCAddr is the address of the gRPC API, GRPC_ADDR.
+	GRPCAddr string
+
+	// SourceHost is the website the ETF data is scraped from, SOURCE_HOST.
+	SourceHost string
+	// UpdateBatchSize is the number of scraped ETFs stored per transaction, UPDATE_BATCH_SIZE.
+	UpdateBatchSize int
+	// UpdateAtomic stores each scraping run in a single transaction, UPDATE_ATOMIC.
+	UpdateAtomic bool
+	// ReadyMaxDataAge is the age of the newest ETF data after which /readyz
+	// fails, READY_MAX_DATA_AGE. It allows for the daily run and its retries.
+	ReadyMaxDataAge time.Duration
+
+	// RateLimits are the token buckets and daily quotas per role and for
+	// anonymous clients. Each limit is set with RATE_LIMIT_<ROLE>_RATE in
+	// requests per second, RATE_LIMIT_<ROLE>_BURST and RATE_LIMIT_<ROLE>_DAILY_QUOTA
+	// (0 is unlimited), ROLE is VIEWER, ANALYST, ADMIN or ANONYMOUS.
+	RateLimits RateLimitConfig
+
+	// AdminUsername and AdminPassword are the credentials of the admin user
+	// created on the first start, ADMIN_USERNAME and ADMIN_PASSWORD. Without a
+	// password a random one is generated and logged once.
+	AdminUsername string
+	AdminPassword string
+
+	// JWTSecret signs HS256 access tokens, JWT_SECRET. It has no default, the
+	// server refuses to start without it unless JWTKeysDir is set.
+	JWTSecret string
+	// JWTKeysDir holds PEM encoded RS256/ES256 keys named <kid>.pem, tokens are
+	// signed with JWTActiveKeyID. Leave it empty to sign with JWTSecret (HS256).
+	// JWT_KEYS_DIR and JWT_ACTIVE_KEY_ID.
+	JWTKeysDir     string
+	JWTActiveKeyID string
+	// JWTSecretValidUntil keeps HS256 tokens signed with JWTSecret valid until
+	// the given time after switching to JWTKeysDir, JWT_SECRET_VALID_UNTIL in
+	// RFC 3339 format. Without it the secret is not accepted at all once keys are configured.
+	JWTSecretValidUntil time.Time
+}
+
+// LoadConfig reads the configuration from the environment. Unset variables
+// default to the settings of the docker-compose development setup.
+func LoadConfig() Config {
+	return Config{
+		DBDriver:        getEnv("DB_DRIVER", DriverPostgres),
+		DBPath:          getEnv("DB_PATH", "etf.db"),
+		DBHost:          getEnv("DB_HOST", "127.0.0.1"),
+		DBPort:          getEnv("DB_PORT", "5432"),
+		DBUser:          getEnv("DB_USER", "admin"),
+		DBPassword:      getEnv("DB_PASSWORD", "admin"),
+		DBName:          getEnv("DB_NAME", "database"),
+		AutoMigrate:     getEnvBool("AUTO_MIGRATE", true),
+		ServerAddr:      getEnv("SERVER_ADDR", ":8080"),
+		GRPCAddr:        getEnv("GRPC_ADDR", ":9090"),
+		SourceHost:      getEnv("SOURCE_HOST", "https://www.ssga.com"),
+		UpdateBatchSize: getEnvInt("UPDATE_BATCH_SIZE", DefaultUpdaterConfig().BatchSize),
+		UpdateAtomic:    getEnvBool("UPDATE_ATOMIC", false),
+		ReadyMaxDataAge: getEnvDuration("READY_MAX_DATA_AGE", 30*time.Hour),
+		RateLimits:      getEnvRateLimits(DefaultRateLimitConfig()),
+		AdminUsername:   getEnv("ADMIN_USERNAME", "admin"),
+		AdminPassword:   getEnv("ADMIN_PASSWORD", ""),
+		JWTSecret:       getEnv("JWT_SECRET", ""),
+		JWTKeysDir:      getEnv("JWT_KEYS_DIR", ""),
+		JWTActiveKeyID:  getEnv("JWT_ACTIVE_KEY_ID", ""),
+
+		JWTSecretValidUntil: getEnvTime("JWT_SECRET_VALID_UNTIL", time.Time{}),
+	}
+}
+
+// UpdaterConfig returns the settings of the DailyDataUpdater.
+func (c Config) UpdaterConfig() UpdaterConfig {
+	return UpdaterConfig{
+		BatchSize: c.UpdateBatchSize,
+		Atomic:    c.UpdateAtomic,
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+// getEnvInt is getEnv for integers, values that are not a number are ignored.
+func getEnvInt(key string, fallback int) int {
+	if n, err := strconv.Atoi(getEnv(key, "")); err == nil {
+		return n
+	}
+	return fallback
+}
+
+// getEnvFloat is getEnv for floating point numbers, values that are not a number are ignored.
+func getEnvFloat(key string, fallback float64) float64 {
+	if f, err := strconv.ParseFloat(getEnv(key, ""), 64); err == nil {
+		return f
+	}
+	return fallback
+}
+
+// getEnvBool is getEnv for booleans, values strconv.ParseBool rejects are ignored.
+func getEnvBool(key string, fallback bool) bool {
+	if b, err := strconv.ParseBool(getEnv(key, "")); err == nil {
+		return b
+	}
+	return fallback
+}
+
+// getEnvDuration is getEnv for durations such as 30h, values time.ParseDuration rejects are ignored.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(getEnv(key, "")); err == nil {
+		return d
+	}
+	return fallback
+}
+
+// getEnvTime is getEnv for RFC 3339 timestamps, values time.Parse rejects are ignored.
+func getEnvTime(key string, fallback time.Time) time.Time {
+	if t, err := time.Parse(time.RFC3339, getEnv(key, "")); err == nil {
+		return t
+	}
+	return fallback
+}
+
+// getEnvRateLimits reads the RATE_LIMIT_<ROLE>_* variables of every role in
+// fallback and of anonymous clients, limits that are not set keep their fallback.
+func getEnvRateLimits(fallback RateLimitConfig) RateLimitConfig {
+	config := RateLimitConfig{
+		Roles:     make(map[models.Role]RateLimit, len(fallback.Roles)),
+		Anonymous: getEnvRateLimit("RATE_LIMIT_ANONYMOUS", fallback.Anonymous),
+	}
+
+	for role, limit := range fallback.Roles {
+		config.Roles[role] = getEnvRateLimit("RATE_LIMIT_"+strings.ToUpper(string(role)), limit)
+	}
+
+	return config
+}
+
+func getEnvRateLimit(prefix string, fallback RateLimit) RateLimit {
+	return RateLimit{
+		Rate:       getEnvFloat(prefix+"_RATE", fallback.Rate),
+		Burst:      getEnvInt(prefix+"_BURST", fallback.Burst),
+		DailyQuota: getEnvInt(prefix+"_DAILY_QUOTA", fallback.DailyQuota),
+	}
+}
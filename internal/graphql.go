@@ -0,0 +1,637 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+
+	"awesomeProject/models"
+)
+
+// viewerETFLimit is the number of ETFs a GraphQL query or a gRPC
+// WatchUpdates stream of a principal without the analyst role may cover.
+// Reading the data of all ETFs at once is reserved for analysts, like /secured/export.
+const viewerETFLimit = 10
+
+// graphQLETFsContextKey stores the graphQLETFs of the current query in its context.
+const graphQLETFsContextKey contextKey = "graphql_etfs"
+
+// graphQLETFs collects the ETFs a query resolved, for the access check and the audit log.
+type graphQLETFs struct {
+	mu      sync.Mutex
+	role    models.Role
+	bulk    bool
+	tickers []string
+	seen    map[string]bool
+}
+
+// add records the resolved tickers. It fails when a principal without the
+// analyst role exceeds viewerETFLimit.
+func (e *graphQLETFs) add(tickers ...string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, ticker := range tickers {
+		ticker = strings.ToUpper(ticker)
+		if e.seen[ticker] {
+			continue
+		}
+
+		if !e.role.Includes(models.RoleAnalyst) && len(e.tickers) >= viewerETFLimit {
+			return fmt.Errorf("queries over more than %d ETFs require the %s role", viewerETFLimit, models.RoleAnalyst)
+		}
+
+		e.seen[ticker] = true
+		e.tickers = append(e.tickers, ticker)
+	}
+
+	return nil
+}
+
+// bulkQuery records a query over all ETFs, which only analysts may run.
+func (e *graphQLETFs) bulkQuery() error {
+	if !e.role.Includes(models.RoleAnalyst) {
+		return fmt.Errorf("queries without a tickers filter require the %s role", models.RoleAnalyst)
+	}
+
+	e.mu.Lock()
+	e.bulk = true
+	e.mu.Unlock()
+
+	return nil
+}
+
+// annotateAudit sets the resolved tickers as resource of the audit event, a
+// query over all ETFs is recorded as an export.
+func (e *graphQLETFs) annotateAudit(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.bulk {
+		annotateAudit(ctx, models.AuditETFExport, "*")
+		return
+	}
+	annotateAudit(ctx, models.AuditETFRead, strings.Join(e.tickers, ","))
+}
+
+// newGraphQLContext returns the context a query of the principal of ctx is resolved with.
+func newGraphQLContext(ctx context.Context) (context.Context, *graphQLETFs) {
+	etfs := &graphQLETFs{seen: map[string]bool{}}
+	if p := principalFromContext(ctx); p != nil {
+		etfs.role = p.Role
+	}
+	return context.WithValue(ctx, graphQLETFsContextKey, etfs), etfs
+}
+
+// graphQLETFsFromContext returns the graphQLETFs of the query. Queries run
+// without newGraphQLContext get a fresh one without any role.
+func graphQLETFsFromContext(ctx context.Context) *graphQLETFs {
+	if etfs, ok := ctx.Value(graphQLETFsContextKey).(*graphQLETFs); ok {
+		return etfs
+	}
+	return &graphQLETFs{seen: map[string]bool{}}
+}
+
+// GraphQLRequest is the body of a POST /graphql request.
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// GraphQLHandler function for querying ETF data with GraphQL.
+// Queries are read from the JSON body of POST requests or the query parameter of GET requests.
+func (h Handlers) GraphQLHandler(w http.ResponseWriter, r *http.Request) {
+	var req GraphQLRequest
+	if r.Method == http.MethodGet {
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+	} else if err := readJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, etfs := newGraphQLContext(r.Context())
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.graphQL,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+
+	etfs.annotateAudit(r.Context())
+
+	WriteJSONResponse(w, result)
+}
+
+// newGraphQLSchema builds the GraphQL schema over the ETF data of the server.
+func newGraphQLSchema(server *Server) (graphql.Schema, error) {
+	limitArg := &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "Return at most this many entries",
+	}
+	minWeightArg := &graphql.ArgumentConfig{
+		Type:        graphql.Float,
+		Description: "Only return entries with at least this weight in percent",
+	}
+
+	weightType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Weight",
+		Description: "Share of a sector or country in a fund",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"weight": &graphql.Field{
+				Type:        graphql.Float,
+				Description: "Weight in percent",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return parseWeight(p.Source.(models.WeightData).Weight), nil
+				},
+			},
+		},
+	})
+
+	holdingType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Holding",
+		Description: "A position of a fund",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"sharesHeld": &graphql.Field{
+				Type: graphql.Float,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if n, ok := parseNumber(p.Source.(models.Holding).SharesHeld); ok {
+						return n, nil
+					}
+					return nil, nil
+				},
+			},
+			"weight": &graphql.Field{
+				Type:        graphql.Float,
+				Description: "Weight in percent",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return parseWeight(p.Source.(models.Holding).Weight), nil
+				},
+			},
+		},
+	})
+
+	characteristicsType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "FundCharacteristics",
+		Description: "Key facts of a fund, null when the fund page does not show them",
+		Fields: graphql.Fields{
+			"nav": &graphql.Field{
+				Type: graphql.Float,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return floatValue(p.Source.(*models.FundCharacteristics).NAV), nil
+				},
+			},
+			"marketPrice": &graphql.Field{
+				Type: graphql.Float,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return floatValue(p.Source.(*models.FundCharacteristics).MarketPrice), nil
+				},
+			},
+			"grossExpenseRatio": &graphql.Field{
+				Type:        graphql.Float,
+				Description: "Gross expense ratio in percent",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return floatValue(p.Source.(*models.FundCharacteristics).GrossExpenseRatio), nil
+				},
+			},
+			"aum": &graphql.Field{
+				Type:        graphql.Float,
+				Description: "Assets under management in the base currency of the fund",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return floatValue(p.Source.(*models.FundCharacteristics).AUM), nil
+				},
+			},
+			"numberOfHoldings": &graphql.Field{
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if n := p.Source.(*models.FundCharacteristics).NumberOfHoldings; n != nil {
+						return *n, nil
+					}
+					return nil, nil
+				},
+			},
+			"inceptionDate": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Inception date formatted as YYYY-MM-DD",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return stringValue(p.Source.(*models.FundCharacteristics).InceptionDate), nil
+				},
+			},
+			"benchmark": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return stringValue(p.Source.(*models.FundCharacteristics).Benchmark), nil
+				},
+			},
+			"primaryExchange": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return stringValue(p.Source.(*models.FundCharacteristics).PrimaryExchange), nil
+				},
+			},
+		},
+	})
+
+	weightListField := func(description string, list func(*models.ETFData) []models.WeightData) *graphql.Field {
+		return &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(weightType))),
+			Description: description,
+			Args: graphql.FieldConfigArgument{
+				"limit":     limitArg,
+				"minWeight": minWeightArg,
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return filterWeights(list(p.Source.(*models.ETFData)), p.Args), nil
+			},
+		}
+	}
+
+	breakdownLevelValues := graphql.EnumValueConfigMap{}
+	for _, level := range models.BreakdownLevels {
+		breakdownLevelValues[strings.ToUpper(string(level))] = &graphql.EnumValueConfig{Value: level}
+	}
+
+	breakdownLevelType := graphql.NewEnum(graphql.EnumConfig{
+		Name:        "BreakdownLevel",
+		Description: "Classification level of a breakdown",
+		Values:      breakdownLevelValues,
+	})
+
+	breakdownType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Breakdown",
+		Description: "Weights of a fund at one classification level",
+		Fields: graphql.Fields{
+			"level": &graphql.Field{
+				Type: graphql.NewNonNull(breakdownLevelType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(models.Breakdown).Level, nil
+				},
+			},
+			"weights": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(weightType))),
+				Args: graphql.FieldConfigArgument{
+					"limit":     limitArg,
+					"minWeight": minWeightArg,
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return filterWeights(p.Source.(models.Breakdown).Weights, p.Args), nil
+				},
+			},
+		},
+	})
+
+	assetClassValues := graphql.EnumValueConfigMap{}
+	for _, assetClass := range []models.AssetClass{
+		models.AssetClassEquity,
+		models.AssetClassFixedIncome,
+		models.AssetClassMultiAsset,
+		models.AssetClassCommodity,
+		models.AssetClassAlternative,
+	} {
+		assetClassValues[strings.ToUpper(string(assetClass))] = &graphql.EnumValueConfig{Value: assetClass}
+	}
+
+	assetClassType := graphql.NewEnum(graphql.EnumConfig{
+		Name:        "AssetClass",
+		Description: "Asset class of a fund",
+		Values:      assetClassValues,
+	})
+
+	fixedIncomeWeightsField := func(description string, list func(*models.FixedIncome) []models.WeightData) *graphql.Field {
+		return &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(weightType))),
+			Description: description,
+			Args: graphql.FieldConfigArgument{
+				"limit":     limitArg,
+				"minWeight": minWeightArg,
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return filterWeights(list(p.Source.(*models.FixedIncome)), p.Args), nil
+			},
+		}
+	}
+
+	fixedIncomeType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "FixedIncome",
+		Description: "Bond characteristics of a fund, null when the fund page does not show them",
+		Fields: graphql.Fields{
+			"duration": &graphql.Field{
+				Type:        graphql.Float,
+				Description: "Effective or modified duration in years",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return floatValue(p.Source.(*models.FixedIncome).Duration), nil
+				},
+			},
+			"yieldToMaturity": &graphql.Field{
+				Type:        graphql.Float,
+				Description: "Yield to maturity in percent",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return floatValue(p.Source.(*models.FixedIncome).YieldToMaturity), nil
+				},
+			},
+			"yieldToWorst": &graphql.Field{
+				Type:        graphql.Float,
+				Description: "Yield to worst in percent",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return floatValue(p.Source.(*models.FixedIncome).YieldToWorst), nil
+				},
+			},
+			"creditQuality": fixedIncomeWeightsField("Breakdown by credit rating", func(fixedIncome *models.FixedIncome) []models.WeightData {
+				return fixedIncome.CreditQuality
+			}),
+			"maturityLadder": fixedIncomeWeightsField("Breakdown by time to maturity", func(fixedIncome *models.FixedIncome) []models.WeightData {
+				return fixedIncome.MaturityLadder
+			}),
+		},
+	})
+
+	etfType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ETF",
+		Description: "An exchange-traded fund",
+		Fields: graphql.Fields{
+			"ticker": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*models.ETFData).Name, nil
+				},
+			},
+			"description": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*models.ETFData).Description, nil
+				},
+			},
+			"assetClass": &graphql.Field{
+				Type: assetClassType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*models.ETFData).AssetClass, nil
+				},
+			},
+			"fixedIncome": &graphql.Field{
+				Type: fixedIncomeType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if fixedIncome := p.Source.(*models.ETFData).FixedIncome; fixedIncome != nil {
+						return fixedIncome, nil
+					}
+					return nil, nil
+				},
+			},
+			"characteristics": &graphql.Field{
+				Type: characteristicsType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if characteristics := p.Source.(*models.ETFData).Characteristics; characteristics != nil {
+						return characteristics, nil
+					}
+					return nil, nil
+				},
+			},
+			"topHoldings": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(holdingType))),
+				Description: "Top holdings ordered by weight as published by the fund",
+				Args:        graphql.FieldConfigArgument{"limit": limitArg},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return limitSlice(p.Source.(*models.ETFData).TopHoldings, p.Args), nil
+				},
+			},
+			"indexTopHoldings": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(holdingType))),
+				Description: "Top holdings of the benchmark index ordered by weight, without shares held",
+				Args:        graphql.FieldConfigArgument{"limit": limitArg},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return limitSlice(p.Source.(*models.ETFData).IndexTopHoldings, p.Args), nil
+				},
+			},
+			"sectors": weightListField("Sector breakdown", func(etf *models.ETFData) []models.WeightData {
+				return etf.Sectors
+			}),
+			"countries": weightListField("Geographical breakdown", func(etf *models.ETFData) []models.WeightData {
+				return etf.Countries
+			}),
+			"breakdowns": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(breakdownType))),
+				Description: "Sector, industry and sub-industry breakdowns the fund page shows",
+				Args: graphql.FieldConfigArgument{
+					"level": &graphql.ArgumentConfig{Type: breakdownLevelType, Description: "Only return the breakdown of this level"},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					result := []models.Breakdown{}
+					for _, breakdown := range p.Source.(*models.ETFData).Breakdowns {
+						if level, ok := p.Args["level"].(models.BreakdownLevel); ok && breakdown.Level != level {
+							continue
+						}
+						result = append(result, breakdown)
+					}
+					return result, nil
+				},
+			},
+		},
+	})
+
+	weightFilterType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "WeightFilter",
+		Description: "Matches funds with a sector or country whose name contains name, ignoring case, within the weight bounds",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"minWeight": &graphql.InputObjectFieldConfig{Type: graphql.Float},
+			"maxWeight": &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		},
+	})
+
+	etfFilterType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "ETFFilter",
+		Description: "All given conditions have to match",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"tickers": &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"sector":  &graphql.InputObjectFieldConfig{Type: weightFilterType},
+			"country": &graphql.InputObjectFieldConfig{Type: weightFilterType},
+			"holding": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "Matches funds with a top holding whose name contains this, ignoring case",
+			},
+			"assetClass": &graphql.InputObjectFieldConfig{
+				Type:        assetClassType,
+				Description: "Matches funds of this asset class",
+			},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"etfs": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(etfType))),
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{Type: etfFilterType},
+					"limit":  limitArg,
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					resolved := graphQLETFsFromContext(p.Context)
+
+					filter, _ := p.Args["filter"].(map[string]interface{})
+					if _, ok := filter["tickers"].([]interface{}); !ok {
+						if err := resolved.bulkQuery(); err != nil {
+							return nil, err
+						}
+					}
+
+					etfs, err := server.ExportETFs()
+					if err != nil {
+						return nil, err
+					}
+
+					var result []*models.ETFData
+					for i := range etfs {
+						if matchesETFFilter(&etfs[i], filter) {
+							result = append(result, &etfs[i])
+						}
+					}
+					result = limitSlice(result, p.Args)
+
+					for _, etf := range result {
+						if err := resolved.add(etf.Name); err != nil {
+							return nil, err
+						}
+					}
+
+					return result, nil
+				},
+			},
+			"etf": &graphql.Field{
+				Type: etfType,
+				Args: graphql.FieldConfigArgument{
+					"ticker": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					etf, err := server.GetETF(p.Args["ticker"].(string))
+					if err != nil {
+						return nil, fmt.Errorf("could not get ETF: %w", err)
+					}
+
+					if err := graphQLETFsFromContext(p.Context).add(etf.Name); err != nil {
+						return nil, err
+					}
+					return etf, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+func matchesETFFilter(etf *models.ETFData, filter map[string]interface{}) bool {
+	if tickers, ok := filter["tickers"].([]interface{}); ok {
+		found := false
+		for _, ticker := range tickers {
+			if strings.EqualFold(ticker.(string), etf.Name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if assetClass, ok := filter["assetClass"].(models.AssetClass); ok && etf.AssetClass != assetClass {
+		return false
+	}
+
+	if sector, ok := filter["sector"].(map[string]interface{}); ok && !matchesWeightFilter(etf.Sectors, sector) {
+		return false
+	}
+
+	if country, ok := filter["country"].(map[string]interface{}); ok && !matchesWeightFilter(etf.Countries, country) {
+		return false
+	}
+
+	if holding, ok := filter["holding"].(string); ok {
+		found := false
+		for _, h := range etf.TopHoldings {
+			if containsFold(h.Name, holding) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesWeightFilter(items []models.WeightData, filter map[string]interface{}) bool {
+	name, _ := filter["name"].(string)
+	minWeight, hasMin := filter["minWeight"].(float64)
+	maxWeight, hasMax := filter["maxWeight"].(float64)
+
+	for _, item := range items {
+		if !containsFold(item.Name, name) {
+			continue
+		}
+
+		weight := parseWeight(item.Weight)
+		if hasMin && weight < minWeight {
+			continue
+		}
+		if hasMax && weight > maxWeight {
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+// filterWeights applies the limit and minWeight arguments to items.
+func filterWeights(items []models.WeightData, args map[string]interface{}) []models.WeightData {
+	var result []models.WeightData
+	for _, item := range items {
+		if minWeight, ok := args["minWeight"].(float64); ok && parseWeight(item.Weight) < minWeight {
+			continue
+		}
+		result = append(result, item)
+	}
+	return limitSlice(result, args)
+}
+
+// limitSlice applies the optional limit argument to a slice.
+func limitSlice[T any](items []T, args map[string]interface{}) []T {
+	if limit, ok := args["limit"].(int); ok && limit >= 0 && limit < len(items) {
+		return items[:limit]
+	}
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// floatValue returns the number or nil for null, a typed nil pointer would not serialize as null.
+func floatValue(n *float64) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+// stringValue returns nil for empty strings so that facts missing on the fund page are null.
+func stringValue(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,437 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"awesomeProject/models"
+	"awesomeProject/proto/etfpb"
+)
+
+// grpcAuditMethod is the method of audit events recorded for gRPC calls, their path is the full gRPC method name.
+const grpcAuditMethod = "GRPC"
+
+// grpcRoles lists the gRPC methods that need more than the viewer role,
+// matching the analytics routes of the REST API.
+var grpcRoles = map[string]models.Role{
+	etfpb.ETFService_GetOverlap_FullMethodName:  models.RoleAnalyst,
+	etfpb.ETFService_GetExposure_FullMethodName: models.RoleAnalyst,
+	etfpb.ETFService_GetTracking_FullMethodName: models.RoleAnalyst,
+}
+
+// NewGRPCServer creates a gRPC server exposing the ETF data with the same
+// authentication, rate limits and audit log as the REST API.
+func NewGRPCServer(h *Handlers) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(h.grpcUnaryInterceptor),
+		grpc.ChainStreamInterceptor(h.grpcStreamInterceptor),
+	)
+
+	etfpb.RegisterETFServiceServer(s, &grpcService{server: h.server})
+
+	return s
+}
+
+// grpcService implements the ETFService on top of the Server methods used by the REST handlers.
+type grpcService struct {
+	etfpb.UnimplementedETFServiceServer
+	server *Server
+}
+
+func (s *grpcService) ListETFs(ctx context.Context, req *etfpb.ListETFsRequest) (*etfpb.ListETFsResponse, error) {
+	tickers, err := s.server.GetAllTickers()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &etfpb.ListETFsResponse{Tickers: tickers}, nil
+}
+
+func (s *grpcService) GetETF(ctx context.Context, req *etfpb.GetETFRequest) (*etfpb.ETF, error) {
+	if req.Ticker == "" {
+		return nil, status.Error(codes.InvalidArgument, "ticker is required")
+	}
+
+	annotateAudit(ctx, models.AuditETFRead, req.Ticker)
+
+	etf, err := s.server.GetETF(req.Ticker)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return etfToProto(etf), nil
+}
+
+func (s *grpcService) WatchUpdates(req *etfpb.WatchUpdatesRequest, stream etfpb.ETFService_WatchUpdatesServer) error {
+	if err := checkWatchedTickers(stream.Context(), req.Tickers); err != nil {
+		return err
+	}
+
+	if len(req.Tickers) == 0 {
+		annotateAudit(stream.Context(), models.AuditETFExport, "*")
+	} else {
+		annotateAudit(stream.Context(), models.AuditETFRead, tickersResource(req.Tickers))
+	}
+
+	updates, stop := s.server.WatchUpdates(req.Tickers)
+	defer stop()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+
+			err := stream.Send(&etfpb.ETFUpdate{
+				Etf:       etfToProto(&update.ETF),
+				UpdatedAt: timestamppb.New(update.UpdatedAt),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkWatchedTickers keeps streams of every ETF to analysts and limits the
+// streams of other principals to viewerETFLimit ETFs, like GraphQL queries.
+func checkWatchedTickers(ctx context.Context, tickers []string) error {
+	p := principalFromContext(ctx)
+	if p != nil && p.Role.Includes(models.RoleAnalyst) {
+		return nil
+	}
+
+	if len(tickers) == 0 {
+		return status.Errorf(codes.PermissionDenied, "watching every ETF requires the %s role", models.RoleAnalyst)
+	}
+
+	unique := map[string]bool{}
+	for _, ticker := range tickers {
+		unique[strings.ToUpper(ticker)] = true
+	}
+	if len(unique) > viewerETFLimit {
+		return status.Errorf(codes.PermissionDenied, "watching more than %d ETFs requires the %s role", viewerETFLimit, models.RoleAnalyst)
+	}
+
+	return nil
+}
+
+func (s *grpcService) GetOverlap(ctx context.Context, req *etfpb.GetOverlapRequest) (*etfpb.Overlap, error) {
+	annotateAudit(ctx, models.AuditETFRead, tickersResource(req.Tickers))
+
+	overlap, err := s.server.Overlap(req.Tickers)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	result := &etfpb.Overlap{
+		Tickers: overlap.Tickers,
+		Weight:  overlap.Weight,
+	}
+	for _, holding := range overlap.Holdings {
+		result.Holdings = append(result.Holdings, &etfpb.OverlapHolding{
+			Name:    holding.Name,
+			Weight:  holding.Weight,
+			Weights: holding.Weights,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *grpcService) GetExposure(ctx context.Context, req *etfpb.GetExposureRequest) (*etfpb.Exposure, error) {
+	positions := make([]models.Position, len(req.Positions))
+	tickers := make([]string, len(req.Positions))
+	for i, position := range req.Positions {
+		positions[i] = models.Position{Ticker: position.Ticker, Weight: position.Weight}
+		tickers[i] = position.Ticker
+	}
+
+	annotateAudit(ctx, models.AuditETFRead, tickersResource(tickers))
+
+	exposure, err := s.server.Exposure(positions)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &etfpb.Exposure{
+		Holdings:  exposureWeightsToProto(exposure.Holdings),
+		Sectors:   exposureWeightsToProto(exposure.Sectors),
+		Countries: exposureWeightsToProto(exposure.Countries),
+	}, nil
+}
+
+func (s *grpcService) GetTracking(ctx context.Context, req *etfpb.GetTrackingRequest) (*etfpb.Tracking, error) {
+	if req.Ticker == "" {
+		return nil, status.Error(codes.InvalidArgument, "ticker is required")
+	}
+
+	annotateAudit(ctx, models.AuditETFRead, req.Ticker)
+
+	tracking, err := s.server.Tracking(req.Ticker)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	result := &etfpb.Tracking{
+		Ticker:      tracking.Ticker,
+		Benchmark:   tracking.Benchmark,
+		FundWeight:  tracking.FundWeight,
+		IndexWeight: tracking.IndexWeight,
+		Deviation:   tracking.Deviation,
+	}
+	for _, holding := range tracking.Holdings {
+		result.Holdings = append(result.Holdings, &etfpb.TrackingHolding{
+			Name:        holding.Name,
+			FundWeight:  holding.FundWeight,
+			IndexWeight: holding.IndexWeight,
+			Difference:  holding.Difference,
+		})
+	}
+
+	return result, nil
+}
+
+func (h Handlers) grpcUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, event, err := h.grpcAuthenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := handler(ctx, req)
+	h.recordGRPCAudit(event, err)
+
+	return resp, err
+}
+
+func (h Handlers) grpcStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, event, err := h.grpcAuthenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	err = handler(srv, &grpcServerStream{ServerStream: stream, ctx: ctx})
+	h.recordGRPCAudit(event, err)
+
+	return err
+}
+
+// grpcAuthenticate checks the credentials in the metadata of a call, the role
+// the method needs and the rate limit of the client. It returns the context
+// for the service method with the principal and the audit event of the call.
+func (h Handlers) grpcAuthenticate(ctx context.Context, method string) (context.Context, *models.AuditEvent, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := metadataValue(md, requestIDHeader)
+	if !validRequestID(requestID) {
+		var err error
+		if requestID, err = randomID(8); err != nil {
+			return nil, nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+
+	event := &models.AuditEvent{
+		OccurredAt: time.Now(),
+		Type:       models.AuditRequest,
+		IP:         peerIP(ctx),
+		RequestID:  requestID,
+		Method:     grpcAuditMethod,
+		Path:       method,
+	}
+
+	p, err := h.authenticateCredentials(metadataValue(md, apiKeyHeader), metadataValue(md, "Authorization"))
+	if err != nil {
+		event.Type = models.AuditAuthFailed
+		event.Details = map[string]string{"reason": err.Error()}
+		h.recordGRPCAudit(event, grpcError(err))
+		return nil, nil, grpcError(err)
+	}
+
+	event.Username = p.Username
+	event.Details = authDetails(p)
+
+	if role, ok := grpcRoles[method]; ok && !p.Role.Includes(role) {
+		err = status.Errorf(codes.PermissionDenied, "%s needs at least the %s role", method, role)
+		h.recordGRPCAudit(event, err)
+		return nil, nil, err
+	}
+
+	key, limit := h.limiter.principalLimit(p)
+	if ok, _, wait, _ := h.limiter.check(key, limit); !ok {
+		err = status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry in %d seconds", ceilSeconds(wait))
+		h.recordGRPCAudit(event, err)
+		return nil, nil, err
+	}
+
+	ctx = context.WithValue(ctx, principalContextKey, p)
+	ctx = context.WithValue(ctx, requestIDContextKey, requestID)
+	ctx = context.WithValue(ctx, auditContextKey, event)
+
+	return ctx, event, nil
+}
+
+// recordGRPCAudit records the audit event of a finished call with its status code.
+func (h Handlers) recordGRPCAudit(event *models.AuditEvent, err error) {
+	if event.Details == nil {
+		event.Details = map[string]string{}
+	}
+	event.Details["grpc_code"] = status.Code(err).String()
+
+	h.server.RecordAudit(*event)
+}
+
+// grpcServerStream replaces the context of a stream with the authenticated one.
+type grpcServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *grpcServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcError maps an error returned by the server to the matching gRPC status,
+// like errorStatus does for HTTP.
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrInvalidCredentials):
+		return status.Error(codes.Unauthenticated, "invalid credentials")
+	case errors.Is(err, ErrUserDisabled):
+		return status.Error(codes.PermissionDenied, "user is disabled")
+	case errors.Is(err, ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// metadataValue returns the first value of a metadata key, keys are case-insensitive.
+func metadataValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// tickersResource formats tickers as resource of an audit event, no tickers mean all ETFs.
+func tickersResource(tickers []string) string {
+	if len(tickers) == 0 {
+		return "*"
+	}
+	return strings.Join(tickers, ",")
+}
+
+func etfToProto(etf *models.ETFData) *etfpb.ETF {
+	return &etfpb.ETF{
+		Name:             etf.Name,
+		Description:      etf.Description,
+		Characteristics:  characteristicsToProto(etf.Characteristics),
+		TopHoldings:      holdingsToProto(etf.TopHoldings),
+		IndexTopHoldings: holdingsToProto(etf.IndexTopHoldings),
+		Countries:        weightsToProto(etf.Countries),
+		Sectors:          weightsToProto(etf.Sectors),
+		Breakdowns:       breakdownsToProto(etf.Breakdowns),
+		AssetClass:       string(etf.AssetClass),
+		FixedIncome:      fixedIncomeToProto(etf.FixedIncome),
+	}
+}
+
+func fixedIncomeToProto(fixedIncome *models.FixedIncome) *etfpb.FixedIncome {
+	if fixedIncome == nil {
+		return nil
+	}
+
+	return &etfpb.FixedIncome{
+		Duration:        fixedIncome.Duration,
+		YieldToMaturity: fixedIncome.YieldToMaturity,
+		YieldToWorst:    fixedIncome.YieldToWorst,
+		CreditQuality:   weightsToProto(fixedIncome.CreditQuality),
+		MaturityLadder:  weightsToProto(fixedIncome.MaturityLadder),
+	}
+}
+
+func characteristicsToProto(characteristics *models.FundCharacteristics) *etfpb.FundCharacteristics {
+	if characteristics == nil {
+		return nil
+	}
+
+	result := &etfpb.FundCharacteristics{
+		Nav:               characteristics.NAV,
+		MarketPrice:       characteristics.MarketPrice,
+		GrossExpenseRatio: characteristics.GrossExpenseRatio,
+		Aum:               characteristics.AUM,
+		InceptionDate:     characteristics.InceptionDate,
+		Benchmark:         characteristics.Benchmark,
+		PrimaryExchange:   characteristics.PrimaryExchange,
+	}
+
+	if characteristics.NumberOfHoldings != nil {
+		n := int32(*characteristics.NumberOfHoldings)
+		result.NumberOfHoldings = &n
+	}
+
+	return result
+}
+
+func holdingsToProto(holdings []models.Holding) []*etfpb.Holding {
+	result := make([]*etfpb.Holding, len(holdings))
+	for i, h := range holdings {
+		result[i] = &etfpb.Holding{Name: h.Name, SharesHeld: h.SharesHeld, Weight: h.Weight}
+	}
+	return result
+}
+
+func weightsToProto(weights []models.WeightData) []*etfpb.WeightData {
+	result := make([]*etfpb.WeightData, len(weights))
+	for i, w := range weights {
+		result[i] = &etfpb.WeightData{Name: w.Name, Weight: w.Weight}
+	}
+	return result
+}
+
+func breakdownsToProto(breakdowns []models.Breakdown) []*etfpb.Breakdown {
+	result := make([]*etfpb.Breakdown, len(breakdowns))
+	for i, b := range breakdowns {
+		result[i] = &etfpb.Breakdown{Level: string(b.Level), Weights: weightsToProto(b.Weights)}
+	}
+	return result
+}
+
+func exposureWeightsToProto(weights []models.ExposureWeight) []*etfpb.ExposureWeight {
+	result := make([]*etfpb.ExposureWeight, len(weights))
+	for i, w := range weights {
+		result[i] = &etfpb.ExposureWeight{Name: w.Name, Weight: w.Weight}
+	}
+	return result
+}
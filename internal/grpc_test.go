@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"awesomeProject/models"
+	"awesomeProject/proto/etfpb"
+)
+
+// newTestGRPCClient serves the gRPC API of api on an in-memory listener.
+func newTestGRPCClient(t *testing.T, api *testAPI) etfpb.ETFServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(api.handlers)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return etfpb.NewETFServiceClient(conn)
+}
+
+func TestGRPCWatchUpdatesRoles(t *testing.T) {
+	api := newTestAPI(t)
+	client := newTestGRPCClient(t, api)
+
+	tooMany := make([]string, viewerETFLimit+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("ETF%d", i)
+	}
+	tooMany[0] = "SPY"
+
+	tests := []struct {
+		name    string
+		role    models.Role
+		tickers []string
+		code    codes.Code
+	}{
+		{"viewer watching every ETF", models.RoleViewer, nil, codes.PermissionDenied},
+		{"viewer watching too many ETFs", models.RoleViewer, tooMany, codes.PermissionDenied},
+		{"viewer watching some ETFs", models.RoleViewer, []string{"SPY", "spy", "QQQ"}, codes.OK},
+		{"analyst watching every ETF", models.RoleAnalyst, nil, codes.OK},
+		{"analyst watching many ETFs", models.RoleAnalyst, tooMany, codes.OK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+api.tokens[test.role])
+
+			stream, err := client.WatchUpdates(ctx, &etfpb.WatchUpdatesRequest{Tickers: test.tickers})
+			if err != nil {
+				t.Fatalf("WatchUpdates: %v", err)
+			}
+
+			// The stream subscribes after it was opened, publish until an update arrives
+			go func() {
+				for ctx.Err() == nil {
+					api.updates.Publish(models.ETFUpdate{ETF: models.ETFData{Name: "SPY"}, UpdatedAt: time.Now()})
+					time.Sleep(10 * time.Millisecond)
+				}
+			}()
+
+			update, err := stream.Recv()
+			if code := status.Code(err); code != test.code {
+				t.Fatalf("Recv = %v, want code %s", err, test.code)
+			}
+			if err == nil && update.Etf.Name != "SPY" {
+				t.Errorf("update of %s, want SPY", update.Etf.Name)
+			}
+		})
+	}
+}
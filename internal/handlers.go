@@ -3,79 +3,252 @@ package internal
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/mux"
+	"github.com/graphql-go/graphql"
 
 	"awesomeProject/models"
 )
 
+// apiKeyHeader is the header machine-to-machine clients send their API key in.
+const apiKeyHeader = "X-API-Key"
+
+// tokenIssuer is the iss claim of issued access tokens, other services can check it.
+const tokenIssuer = "awesomeProject"
+
+// accessTokenTTL is kept short since access tokens are only checked against
+// revoked sessions, refresh tokens are used to get new ones.
+const accessTokenTTL = 15 * time.Minute
+
+type contextKey string
+
+// principalContextKey stores the authenticated principal in the request context.
+const principalContextKey contextKey = "principal"
+
+// principal describes who is making an authenticated request.
+type principal struct {
+	Username  string
+	Role      models.Role
+	SessionID string
+	APIKeyID  int
+}
+
 type Handlers struct {
-	server    *Server
-	jwtSecret []byte
+	server   *Server
+	keys     *KeySet
+	limiter  *RateLimiter
+	throttle *LoginThrottle
+	health   *HealthChecker
+	graphQL  graphql.Schema
 }
 
-func NewHandler(server *Server, jwtSecret []byte) *Handlers {
+func NewHandler(server *Server, keys *KeySet, limiter *RateLimiter, throttle *LoginThrottle, health *HealthChecker) *Handlers {
+	schema, err := newGraphQLSchema(server)
+	if err != nil {
+		// The schema is static, so this is a programming error
+		panic(fmt.Sprintf("could not build the GraphQL schema: %v", err))
+	}
+
 	return &Handlers{
-		server:    server,
-		jwtSecret: jwtSecret,
+		server:   server,
+		keys:     keys,
+		limiter:  limiter,
+		throttle: throttle,
+		health:   health,
+		graphQL:  schema,
 	}
 }
 
-// LoginHandler function for user login and token generation
+// LoginHandler function for user login and token generation.
+// The credentials are read from a JSON body or from form values.
 func (h Handlers) LoginHandler(w http.ResponseWriter, r *http.Request) {
-	username := r.FormValue("username")
-	password := r.FormValue("password")
+	var req models.LoginRequest
+	if isJSONRequest(r) {
+		if err := readJSON(r, &req); err != nil {
+			writeError(w, err)
+			return
+		}
+	} else {
+		req.Username = r.FormValue("username")
+		req.Password = r.FormValue("password")
+	}
+
+	ip := clientIP(r)
+
+	// Repeated failures delay further attempts and finally lock the username or IP out
+	if wait := h.throttle.Wait(req.Username, ip); wait > 0 {
+		h.audit(r, models.AuditLoginFailed, req.Username, http.StatusTooManyRequests, map[string]string{"reason": "throttled"})
+		tooManyRequests(w, wait)
+		return
+	}
+
+	user, err := h.server.Authenticate(req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) && h.throttle.Failure(req.Username, ip) {
+			h.audit(r, models.AuditLoginLockout, req.Username, http.StatusUnauthorized, nil)
+		}
+		h.audit(r, models.AuditLoginFailed, req.Username, errorStatus(err), map[string]string{"reason": err.Error()})
+		writeError(w, err)
+		return
+	}
+
+	h.throttle.Success(req.Username)
 
-	exists, err := h.server.UserExists(username, password)
+	sessionID, refreshToken, err := h.server.StartSession(user, r.UserAgent(), ip)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	if !exists {
-		w.WriteHeader(http.StatusUnauthorized)
+	h.audit(r, models.AuditLogin, user.Username, http.StatusOK, map[string]string{"session_id": sessionID})
+	h.writeTokenResponse(w, user, sessionID, refreshToken)
+}
+
+// RefreshTokenHandler function for exchanging a refresh token for a new token pair
+func (h Handlers) RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err)
 		return
 	}
 
-	token, err := h.generateToken(username)
+	user, sessionID, refreshToken, err := h.server.RefreshSession(req.RefreshToken)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		h.audit(r, models.AuditAuthFailed, "", errorStatus(err), map[string]string{"reason": "refresh: " + err.Error()})
+		writeError(w, err)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
-	WriteJSONResponse(w, token)
+
+	h.audit(r, models.AuditTokenRefresh, user.Username, http.StatusOK, map[string]string{"session_id": sessionID})
+	h.writeTokenResponse(w, user, sessionID, refreshToken)
 }
 
-// RequireTokenAuthentication middleware function for JWT authentication
+// LogoutHandler function for revoking the session of the presented access token
+func (h Handlers) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+	if p.SessionID == "" {
+		http.Error(w, "API keys have no session, revoke the key instead", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.server.RevokeSession(p.Username, p.SessionID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.audit(r, models.AuditLogout, p.Username, http.StatusNoContent, map[string]string{"session_id": p.SessionID})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RequireTokenAuthentication middleware function for JWT and API key authentication
 func (h Handlers) RequireTokenAuthentication(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		tokenString := r.Header.Get("Authorization")
-		if tokenString == "" {
-			w.WriteHeader(http.StatusUnauthorized)
-			return
-		}
-
-		token, err := jwt.ParseWithClaims(tokenString, &models.Claims{}, func(token *jwt.Token) (interface{}, error) {
-			return h.jwtSecret, nil
-		})
+		p, err := h.authenticateRequest(r)
 		if err != nil {
-			w.WriteHeader(http.StatusUnauthorized)
+			h.audit(r, models.AuditAuthFailed, "", errorStatus(err), map[string]string{"reason": err.Error()})
+			writeError(w, err)
 			return
 		}
 
-		if claims, ok := token.Claims.(*models.Claims); ok && token.Valid {
-			// Token is valid, proceed to the next handler
-			r = r.WithContext(context.WithValue(r.Context(), "username", claims.Username))
-			next.ServeHTTP(w, r)
-		} else {
-			w.WriteHeader(http.StatusUnauthorized)
-		}
+		// Credentials are valid, proceed to the next handler
+		r = r.WithContext(context.WithValue(r.Context(), principalContextKey, p))
+		next.ServeHTTP(w, r)
 	})
 }
 
+// authenticateRequest reads the credentials from the request headers.
+func (h Handlers) authenticateRequest(r *http.Request) (*principal, error) {
+	return h.authenticateCredentials(r.Header.Get(apiKeyHeader), r.Header.Get("Authorization"))
+}
+
+// authenticateCredentials checks the credentials of a request. API keys are
+// accepted in the X-API-Key header or as "Authorization: ApiKey <key>", JWT
+// access tokens as "Authorization: Bearer <token>".
+func (h Handlers) authenticateCredentials(apiKey, authorization string) (*principal, error) {
+	if apiKey != "" {
+		return h.authenticateAPIKey(apiKey)
+	}
+
+	if key, ok := cutPrefixFold(authorization, "ApiKey "); ok {
+		return h.authenticateAPIKey(key)
+	}
+
+	tokenString, _ := cutPrefixFold(authorization, "Bearer ")
+	return h.authenticateToken(tokenString)
+}
+
+func (h Handlers) authenticateAPIKey(key string) (*principal, error) {
+	user, apiKey, err := h.server.AuthenticateAPIKey(strings.TrimSpace(key))
+	if err != nil {
+		return nil, err
+	}
+
+	return &principal{
+		Username: user.Username,
+		Role:     apiKey.Scope,
+		APIKeyID: apiKey.ID,
+	}, nil
+}
+
+func (h Handlers) authenticateToken(tokenString string) (*principal, error) {
+	if tokenString == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &models.Claims{}, h.keys.Keyfunc)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	claims, ok := token.Claims.(*models.Claims)
+	if !ok || !token.Valid || claims.SessionID == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	// Tokens of revoked or expired sessions are rejected before they expire
+	user, err := h.server.SessionUser(claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A token is only valid for the user owning its session. The role is taken
+	// from the user instead of the claims, so role changes apply immediately.
+	if user.Username != claims.Username {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &principal{
+		Username:  user.Username,
+		Role:      user.Role,
+		SessionID: claims.SessionID,
+	}, nil
+}
+
+// RequireRole returns a middleware that only lets principals with at least the given role through.
+// It must run after RequireTokenAuthentication.
+func (h Handlers) RequireRole(role models.Role) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p := principalFromContext(r.Context())
+			if p == nil || !p.Role.Includes(role) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // ListETFSymbolsHandler function for listing available ETF symbols
 func (h Handlers) ListETFSymbolsHandler(w http.ResponseWriter, r *http.Request) {
 	etf, err := h.server.GetAllTickers()
@@ -98,9 +271,11 @@ func (h Handlers) GetETFDataHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	annotateAudit(r.Context(), models.AuditETFRead, ticker)
+
 	etf, err := h.server.GetETF(ticker)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
@@ -108,16 +283,482 @@ func (h Handlers) GetETFDataHandler(w http.ResponseWriter, r *http.Request) {
 	WriteJSONResponse(w, etf)
 }
 
-// Define a function to generate JWT tokens
-func (h Handlers) generateToken(username string) (string, error) {
+// GetHoldingsHandler function for getting a page of the full holdings of an ETF
+func (h Handlers) GetHoldingsHandler(w http.ResponseWriter, r *http.Request) {
+	ticker := mux.Vars(r)["ticker"]
+	query := r.URL.Query()
+
+	page, err := parseIntParam(query.Get("page"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	pageSize, err := parseIntParam(query.Get("page_size"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	annotateAudit(r.Context(), models.AuditETFRead, ticker)
+
+	holdings, err := h.server.GetHoldings(ticker, page, pageSize)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	WriteJSONResponse(w, holdings)
+}
+
+// GetBreakdownsHandler function for getting the sector, industry and sub-industry breakdowns of an ETF
+func (h Handlers) GetBreakdownsHandler(w http.ResponseWriter, r *http.Request) {
+	ticker := mux.Vars(r)["ticker"]
+	level := models.BreakdownLevel(r.URL.Query().Get("level"))
+
+	annotateAudit(r.Context(), models.AuditETFRead, ticker)
+
+	breakdowns, err := h.server.GetBreakdowns(ticker, level)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	WriteJSONResponse(w, breakdowns)
+}
+
+// GetDistributionsHandler function for getting the distributions of an ETF.
+// The optional from and to query parameters bound the ex-date as YYYY-MM-DD.
+func (h Handlers) GetDistributionsHandler(w http.ResponseWriter, r *http.Request) {
+	ticker := mux.Vars(r)["ticker"]
+	query := r.URL.Query()
+
+	from, err := parseDateParam(query.Get("from"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	to, err := parseDateParam(query.Get("to"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	annotateAudit(r.Context(), models.AuditETFRead, ticker)
+
+	distributions, err := h.server.GetDistributions(ticker, from, to)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	WriteJSONResponse(w, distributions)
+}
+
+// ExportETFsHandler function for exporting the data of all ETFs at once
+func (h Handlers) ExportETFsHandler(w http.ResponseWriter, r *http.Request) {
+	annotateAudit(r.Context(), models.AuditETFExport, "*")
+
+	etfs, err := h.server.ExportETFs()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	WriteJSONResponse(w, etfs)
+}
+
+// OverlapHandler function for comparing the top holdings of ETFs.
+// The tickers are passed as comma separated tickers query parameter.
+func (h Handlers) OverlapHandler(w http.ResponseWriter, r *http.Request) {
+	var tickers []string
+	for _, ticker := range strings.Split(r.URL.Query().Get("tickers"), ",") {
+		if ticker = strings.TrimSpace(ticker); ticker != "" {
+			tickers = append(tickers, ticker)
+		}
+	}
+
+	annotateAudit(r.Context(), models.AuditETFRead, tickersResource(tickers))
+
+	overlap, err := h.server.Overlap(tickers)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	WriteJSONResponse(w, overlap)
+}
+
+// TrackingHandler function for comparing the top holdings of an ETF with those of its benchmark index
+func (h Handlers) TrackingHandler(w http.ResponseWriter, r *http.Request) {
+	ticker := mux.Vars(r)["ticker"]
+
+	annotateAudit(r.Context(), models.AuditETFRead, ticker)
+
+	tracking, err := h.server.Tracking(ticker)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	WriteJSONResponse(w, tracking)
+}
+
+// ExposureHandler function for combining the holdings, sectors and countries of a portfolio of ETFs
+func (h Handlers) ExposureHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.ExposureRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	tickers := make([]string, len(req.Positions))
+	for i, position := range req.Positions {
+		tickers[i] = position.Ticker
+	}
+	annotateAudit(r.Context(), models.AuditETFRead, tickersResource(tickers))
+
+	exposure, err := h.server.Exposure(req.Positions)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	WriteJSONResponse(w, exposure)
+}
+
+// JWKSHandler function for publishing the public keys access tokens can be verified with
+func (h Handlers) JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	WriteJSONResponse(w, h.keys.JWKS())
+}
+
+// AuditLogHandler function for querying the audit log.
+// It supports the username, type, resource, request_id, from, to, limit and offset query parameters,
+// from and to are RFC 3339 timestamps.
+func (h Handlers) AuditLogHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := models.AuditFilter{
+		Username:  query.Get("username"),
+		Type:      models.AuditEventType(query.Get("type")),
+		Resource:  query.Get("resource"),
+		RequestID: query.Get("request_id"),
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		writeError(w, err)
+		return
+	}
+	if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+		writeError(w, err)
+		return
+	}
+	if filter.Limit, err = parseIntParam(query.Get("limit")); err != nil {
+		writeError(w, err)
+		return
+	}
+	if filter.Offset, err = parseIntParam(query.Get("offset")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	events, err := h.server.ListAuditEvents(filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	WriteJSONResponse(w, events)
+}
+
+// ListUsersHandler function for listing all users
+func (h Handlers) ListUsersHandler(w http.ResponseWriter, r *http.Request) {
+	users, err := h.server.ListUsers()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	WriteJSONResponse(w, users)
+}
+
+// CreateUserHandler function for creating a new user
+func (h Handlers) CreateUserHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateUserRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	user, err := h.server.CreateUser(req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSONStatus(w, http.StatusCreated, user)
+}
+
+// DisableUserHandler function for disabling a user
+func (h Handlers) DisableUserHandler(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, true)
+}
+
+// EnableUserHandler function for enabling a previously disabled user
+func (h Handlers) EnableUserHandler(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, false)
+}
+
+func (h Handlers) setUserDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	id, ok := h.otherUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.server.SetUserDisabled(id, disabled); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetUserRoleHandler function for changing the role of a user
+func (h Handlers) SetUserRoleHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.otherUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateRoleRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := h.server.SetUserRole(id, req.Role); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteUserHandler function for deleting a user
+func (h Handlers) DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.otherUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.server.DeleteUser(id); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResetPasswordHandler function for setting a new password for any user
+func (h Handlers) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	annotateAudit(r.Context(), models.AuditAdminAction, "user:"+strconv.Itoa(id))
+
+	var req models.ResetPasswordRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := h.server.ResetPassword(id, req.Password); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ChangePasswordHandler function for changing the password of the authenticated user
+func (h Handlers) ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var req models.ChangePasswordRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	p := principalFromContext(r.Context())
+
+	err := h.server.ChangePassword(p.Username, req.CurrentPassword, req.NewPassword, p.SessionID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSessionsHandler function for listing the active sessions of the authenticated user
+func (h Handlers) ListSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+
+	sessions, err := h.server.ListSessions(p.Username, p.SessionID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	WriteJSONResponse(w, sessions)
+}
+
+// RevokeSessionHandler function for revoking one session of the authenticated user
+func (h Handlers) RevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.server.RevokeSession(usernameFromContext(r.Context()), mux.Vars(r)["id"]); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeAllSessionsHandler function for revoking every session of the authenticated user
+func (h Handlers) RevokeAllSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.server.RevokeAllSessions(usernameFromContext(r.Context())); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAPIKeysHandler function for listing the API keys of the authenticated user
+func (h Handlers) ListAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.server.ListAPIKeys(usernameFromContext(r.Context()))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	WriteJSONResponse(w, keys)
+}
+
+// CreateAPIKeyHandler function for creating an API key for the authenticated user.
+// The key is only returned in this response.
+func (h Handlers) CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	p := principalFromContext(r.Context())
+	if p.APIKeyID != 0 {
+		http.Error(w, "API keys cannot create other API keys", http.StatusForbidden)
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+	if err := readJSON(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	key, err := h.server.CreateAPIKey(p.Username, req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSONStatus(w, http.StatusCreated, key)
+}
+
+// RevokeAPIKeyHandler function for revoking an API key of the authenticated user
+func (h Handlers) RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.server.RevokeAPIKey(usernameFromContext(r.Context()), id); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// otherUserID reads the user ID from the path and refuses requests targeting
+// the caller's own account, so an admin cannot lock themselves out.
+func (h Handlers) otherUserID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return 0, false
+	}
+
+	caller, err := h.server.GetUser(usernameFromContext(r.Context()))
+	if err != nil {
+		writeError(w, err)
+		return 0, false
+	}
+
+	if caller.ID == id {
+		http.Error(w, "cannot modify your own account", http.StatusBadRequest)
+		return 0, false
+	}
+
+	annotateAudit(r.Context(), models.AuditAdminAction, "user:"+strconv.Itoa(id))
+
+	return id, true
+}
+
+// writeTokenResponse issues an access token for the session and writes it together with the refresh token.
+func (h Handlers) writeTokenResponse(w http.ResponseWriter, user *models.User, sessionID, refreshToken string) {
+	accessToken, expiresAt, err := h.generateToken(user.Username, user.Role, sessionID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Tokens must not end up in shared caches
+	w.Header().Set("Cache-Control", "no-store")
+	WriteJSONResponse(w, models.TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+		ExpiresAt:    expiresAt,
+		RefreshToken: refreshToken,
+	})
+}
+
+// Define a function to generate JWT tokens, it returns the token and its expiry
+func (h Handlers) generateToken(username string, role models.Role, sessionID string) (string, time.Time, error) {
+	tokenID, err := randomID(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := jwt.TimeFunc()
+	expiresAt := now.Add(accessTokenTTL).Truncate(time.Second)
 	claims := models.Claims{
-		Username: username,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: jwt.TimeFunc().Add(time.Hour * 24).Unix(), // Token expires in 24 hours
+		Username:  username,
+		Role:      role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Issuer:    tokenIssuer,
+			Subject:   username,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(h.jwtSecret)
+
+	token, err := h.keys.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
 }
 
 func WriteJSONResponse(w http.ResponseWriter, data interface{}) {
@@ -138,3 +779,130 @@ func WriteJSONResponse(w http.ResponseWriter, data interface{}) {
 		return
 	}
 }
+
+// writeJSONStatus writes the data as JSON with the given status code.
+func writeJSONStatus(w http.ResponseWriter, status int, data interface{}) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		http.Error(w, "Failed to marshal JSON", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(jsonData)
+}
+
+// writeError maps an error returned by the server to the matching HTTP status code.
+func writeError(w http.ResponseWriter, err error) {
+	status := errorStatus(err)
+	if status == http.StatusBadRequest {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.WriteHeader(status)
+}
+
+// errorStatus returns the HTTP status code for an error returned by the server.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidCredentials):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrUserDisabled):
+		return http.StatusForbidden
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrAlreadyExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseTimeParam parses an optional RFC 3339 query parameter.
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q is not an RFC 3339 timestamp", ErrInvalidInput, value)
+	}
+
+	return &t, nil
+}
+
+// parseDateParam validates an optional YYYY-MM-DD query parameter.
+func parseDateParam(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		return "", fmt.Errorf("%w: %q is not a YYYY-MM-DD date", ErrInvalidInput, value)
+	}
+
+	return value, nil
+}
+
+// parseIntParam parses an optional integer query parameter.
+func parseIntParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a number", ErrInvalidInput, value)
+	}
+
+	return n, nil
+}
+
+// isJSONRequest reports whether the request body is declared as JSON.
+func isJSONRequest(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "application/json"
+}
+
+// readJSON decodes the JSON request body into v.
+func readJSON(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: malformed JSON body", ErrInvalidInput)
+	}
+
+	return nil
+}
+
+// cutPrefixFold removes the prefix from s ignoring case and reports whether it was found.
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// clientIP returns the IP address of the client without the port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func principalFromContext(ctx context.Context) *principal {
+	p, _ := ctx.Value(principalContextKey).(*principal)
+	return p
+}
+
+func usernameFromContext(ctx context.Context) string {
+	if p := principalFromContext(ctx); p != nil {
+		return p.Username
+	}
+	return ""
+}
@@ -0,0 +1,277 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"awesomeProject/models"
+)
+
+func TestRoleAccess(t *testing.T) {
+	api := newTestAPI(t)
+
+	tests := []struct {
+		method string
+		path   string
+		role   models.Role
+		status int
+	}{
+		{"GET", "/secured/etfs", "", http.StatusUnauthorized},
+		{"GET", "/secured/etfs", models.RoleViewer, http.StatusOK},
+		{"GET", "/secured/export", models.RoleViewer, http.StatusForbidden},
+		{"GET", "/secured/export", models.RoleAnalyst, http.StatusOK},
+		{"GET", "/secured/export", models.RoleAdmin, http.StatusOK},
+		{"GET", "/admin/users", models.RoleViewer, http.StatusForbidden},
+		{"GET", "/admin/users", models.RoleAnalyst, http.StatusForbidden},
+		{"GET", "/admin/users", models.RoleAdmin, http.StatusOK},
+		{"GET", "/graphql?query=%7Betfs%7Bticker%7D%7D", "", http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s %s as %q", test.method, test.path, test.role), func(t *testing.T) {
+			if rec := api.do(test.method, test.path, test.role, nil); rec.Code != test.status {
+				t.Errorf("status %d, want %d", rec.Code, test.status)
+			}
+		})
+	}
+}
+
+func TestETFHandlers(t *testing.T) {
+	api := newTestAPI(t)
+
+	rec := api.do("GET", "/secured/etfs", models.RoleViewer, nil)
+	var tickers []string
+	decodeTestJSON(t, rec, &tickers)
+	sort.Strings(tickers)
+	if !reflect.DeepEqual(tickers, []string{"QQQ", "SPY"}) {
+		t.Errorf("tickers = %v", tickers)
+	}
+
+	rec = api.do("GET", "/secured/etf/SPY", models.RoleViewer, nil)
+	var etf models.ETFData
+	decodeTestJSON(t, rec, &etf)
+	if etf.Name != "SPY" || etf.Characteristics == nil || *etf.Characteristics.NumberOfHoldings != 503 {
+		t.Errorf("ETF = %+v", etf)
+	}
+
+	rec = api.do("GET", "/secured/etf/SPY/holdings?page=2&page_size=2", models.RoleViewer, nil)
+	var page models.HoldingsPage
+	decodeTestJSON(t, rec, &page)
+	if page.Page != 2 || page.PageSize != 2 || page.Total != 3 || len(page.Holdings) != 1 || page.Holdings[0].Ticker != "NVDA" {
+		t.Errorf("holdings page = %+v", page)
+	}
+
+	rec = api.do("GET", "/secured/etf/SPY/breakdowns?level=industry", models.RoleViewer, nil)
+	var breakdowns []models.Breakdown
+	decodeTestJSON(t, rec, &breakdowns)
+	if len(breakdowns) != 1 || breakdowns[0].Level != models.BreakdownIndustry {
+		t.Errorf("industry breakdowns = %+v", breakdowns)
+	}
+
+	rec = api.do("GET", "/secured/etf/SPY/distributions?to=2024-12-31", models.RoleViewer, nil)
+	var history models.DistributionHistory
+	decodeTestJSON(t, rec, &history)
+	if len(history.Distributions) != 1 || history.Distributions[0].ExDate != "2024-03-15" {
+		t.Errorf("distributions until 2024 = %+v", history)
+	}
+}
+
+func TestHoldingsPageOutOfRange(t *testing.T) {
+	api := newTestAPI(t)
+
+	tests := []struct {
+		query  string
+		status int
+	}{
+		// (page-1)*page_size overflows to a negative offset
+		{"page=4611686018427387905&page_size=2", http.StatusBadRequest},
+		{"page=9223372036854775807", http.StatusBadRequest},
+		{"page_size=1001", http.StatusBadRequest},
+		// Pages after the last position are empty
+		{"page=1000000&page_size=1000", http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			rec := api.do("GET", "/secured/etf/SPY/holdings?"+test.query, models.RoleViewer, nil)
+			if rec.Code != test.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, test.status, rec.Body)
+			}
+		})
+	}
+}
+
+func TestPasswordLength(t *testing.T) {
+	api := newTestAPI(t)
+	target := api.users[models.RoleViewer]
+
+	// bcrypt hashes at most 72 bytes, "é" takes two
+	longest := strings.Repeat("x", maxPasswordLength)
+	tooLong := strings.Repeat("x", maxPasswordLength+1)
+	tooManyBytes := strings.Repeat("é", maxPasswordLength/2+1)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		role     models.Role
+		password func(string) interface{}
+	}{
+		{"create user", "POST", "/admin/users", models.RoleAdmin, func(password string) interface{} {
+			return models.CreateUserRequest{Username: "user" + strconv.Itoa(len(password)), Password: password}
+		}},
+		{"reset password", "PUT", fmt.Sprintf("/admin/users/%d/password", target.ID), models.RoleAdmin, func(password string) interface{} {
+			return models.ResetPasswordRequest{Password: password}
+		}},
+		{"change password", "PUT", "/secured/me/password", models.RoleAnalyst, func(password string) interface{} {
+			return models.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: password}
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, password := range []string{tooLong, tooManyBytes} {
+				rec := api.do(test.method, test.path, test.role, test.password(password))
+				if rec.Code != http.StatusBadRequest {
+					t.Errorf("%d bytes: status %d, want %d", len(password), rec.Code, http.StatusBadRequest)
+				}
+			}
+
+			rec := api.do(test.method, test.path, test.role, test.password(tooManyBytes))
+			if !strings.Contains(rec.Body.String(), "at most 72 bytes") {
+				t.Errorf("error message %q does not tell the limit", rec.Body)
+			}
+
+			if rec := api.do(test.method, test.path, test.role, test.password(longest)); rec.Code >= 300 {
+				t.Errorf("%d bytes: status %d: %s", len(longest), rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestAnalyticsHandlers(t *testing.T) {
+	api := newTestAPI(t)
+
+	rec := api.do("GET", "/secured/overlap?tickers=SPY,QQQ", models.RoleAnalyst, nil)
+	var overlap models.Overlap
+	decodeTestJSON(t, rec, &overlap)
+	if len(overlap.Holdings) != 1 || overlap.Holdings[0].Name != "Microsoft Corporation" || overlap.Weight != 6.9 {
+		t.Errorf("overlap = %+v, want Microsoft Corporation with 6.9%%", overlap)
+	}
+
+	rec = api.do("POST", "/secured/exposure", models.RoleAnalyst, models.ExposureRequest{Positions: []models.Position{{Ticker: "SPY", Weight: 50}, {Ticker: "QQQ", Weight: 50}}})
+	var exposure models.Exposure
+	decodeTestJSON(t, rec, &exposure)
+	if len(exposure.Sectors) != 1 || exposure.Sectors[0].Name != "Information Technology" || exposure.Sectors[0].Weight != 39.5 {
+		t.Errorf("sector exposure = %+v, want Information Technology with 39.5%%", exposure.Sectors)
+	}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	api := newTestAPI(t)
+	tokens := api.login("viewer")
+
+	rec := api.request("POST", "/token/refresh", "", models.RefreshRequest{RefreshToken: tokens.RefreshToken})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("refresh: status %d", rec.Code)
+	}
+	var refreshed models.TokenResponse
+	decodeTestJSON(t, rec, &refreshed)
+	if refreshed.RefreshToken == tokens.RefreshToken {
+		t.Error("the refresh token was not rotated")
+	}
+	if rec := api.request("GET", "/secured/etfs", refreshed.AccessToken, nil); rec.Code != http.StatusOK {
+		t.Errorf("refreshed access token: status %d", rec.Code)
+	}
+
+	if rec := api.request("POST", "/token/refresh", "", models.RefreshRequest{RefreshToken: tokens.RefreshToken}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("reused refresh token: status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestLogoutRevokesTheSession(t *testing.T) {
+	api := newTestAPI(t)
+	tokens := api.login("viewer")
+
+	if rec := api.request("POST", "/logout", tokens.AccessToken, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("logout: status %d", rec.Code)
+	}
+
+	if rec := api.request("GET", "/secured/etfs", tokens.AccessToken, nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("access token after logout: status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := api.request("POST", "/token/refresh", "", models.RefreshRequest{RefreshToken: tokens.RefreshToken}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("refresh token after logout: status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestUserChangesApplyToIssuedTokens(t *testing.T) {
+	api := newTestAPI(t)
+	viewer := api.users[models.RoleViewer]
+
+	rec := api.do("PUT", fmt.Sprintf("/admin/users/%d/role", viewer.ID), models.RoleAdmin, models.UpdateRoleRequest{Role: models.RoleAnalyst})
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("set role: status %d", rec.Code)
+	}
+	if rec := api.do("GET", "/secured/export", models.RoleViewer, nil); rec.Code != http.StatusOK {
+		t.Errorf("export after the promotion: status %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	rec = api.do("POST", fmt.Sprintf("/admin/users/%d/disable", viewer.ID), models.RoleAdmin, nil)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("disable: status %d", rec.Code)
+	}
+	// Disabling a user revokes their sessions
+	if rec := api.do("GET", "/secured/etfs", models.RoleViewer, nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("token of a disabled user: status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	admin := api.users[models.RoleAdmin]
+	rec = api.do("POST", fmt.Sprintf("/admin/users/%d/disable", admin.ID), models.RoleAdmin, nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("disable own account: status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	api := newTestAPI(t)
+
+	rec := api.do("POST", "/secured/me/apikeys", models.RoleAnalyst, models.CreateAPIKeyRequest{Name: "reports", Scope: models.RoleViewer})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create API key: status %d: %s", rec.Code, rec.Body)
+	}
+	var key models.CreatedAPIKey
+	decodeTestJSON(t, rec, &key)
+
+	withKey := func(method, path string, body interface{}) int {
+		req := newTestRequest(t, method, path, body)
+		req.Header.Set(apiKeyHeader, key.Key)
+		rec := httptest.NewRecorder()
+		api.handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if status := withKey("GET", "/secured/etfs", nil); status != http.StatusOK {
+		t.Errorf("request with the API key: status %d", status)
+	}
+	if status := withKey("GET", "/secured/export", nil); status != http.StatusForbidden {
+		t.Errorf("request outside the scope of the API key: status %d, want %d", status, http.StatusForbidden)
+	}
+	if status := withKey("POST", "/secured/me/apikeys", models.CreateAPIKeyRequest{Name: "other"}); status != http.StatusForbidden {
+		t.Errorf("API key creating an API key: status %d, want %d", status, http.StatusForbidden)
+	}
+
+	rec = api.do("DELETE", fmt.Sprintf("/secured/me/apikeys/%d", key.ID), models.RoleAnalyst, nil)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke API key: status %d", rec.Code)
+	}
+	if status := withKey("GET", "/secured/etfs", nil); status != http.StatusUnauthorized {
+		t.Errorf("revoked API key: status %d, want %d", status, http.StatusUnauthorized)
+	}
+}
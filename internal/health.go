@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"awesomeProject/models"
+)
+
+// HealthChecker tells whether the server can serve current data.
+type HealthChecker struct {
+	store      Store
+	updater    *DailyDataUpdater
+	maxDataAge time.Duration
+	logger     *logrus.Logger
+}
+
+// NewHealthChecker creates a checker that reports the server as not ready when
+// the newest ETF data is older than maxDataAge. The updater may be nil when
+// the data is scraped by another process.
+func NewHealthChecker(store Store, updater *DailyDataUpdater, maxDataAge time.Duration, logger *logrus.Logger) *HealthChecker {
+	return &HealthChecker{
+		store:      store,
+		updater:    updater,
+		maxDataAge: maxDataAge,
+		logger:     logger,
+	}
+}
+
+// Readiness checks the database, the migrations and the age of the data.
+// The server is ready when Problems is empty.
+func (c *HealthChecker) Readiness() models.Readiness {
+	readiness := models.Readiness{
+		Database:          "ok",
+		MaxDataAgeSeconds: int64(c.maxDataAge / time.Second),
+	}
+
+	if c.updater != nil {
+		readiness.LastUpdate = c.updater.LastRun()
+	}
+
+	// The error may name hosts and users, it is only logged
+	if err := c.store.Ping(); err != nil {
+		c.logger.Errorf("Readiness check could not reach the database: %v", err)
+		readiness.Database = "unreachable"
+		readiness.Problems = append(readiness.Problems, "the database is unreachable")
+		return readiness
+	}
+
+	readiness.Problems = append(readiness.Problems, c.checkMigrations(&readiness)...)
+	readiness.Problems = append(readiness.Problems, c.checkData(&readiness)...)
+	readiness.Ready = len(readiness.Problems) == 0
+
+	return readiness
+}
+
+func (c *HealthChecker) checkMigrations(readiness *models.Readiness) []string {
+	migrator, ok := c.store.(Migrator)
+	if !ok {
+		return nil
+	}
+
+	version, dirty, err := migrator.MigrationVersion()
+	if err != nil {
+		c.logger.Errorf("Readiness check could not read the migration version: %v", err)
+		return []string{"the migration version could not be read"}
+	}
+
+	expected, err := migrator.LatestMigrationVersion()
+	if err != nil {
+		c.logger.Errorf("Readiness check could not read the migrations: %v", err)
+		return []string{"the migrations could not be read"}
+	}
+
+	readiness.MigrationVersion = version
+	readiness.ExpectedMigrationVersion = expected
+
+	if dirty {
+		return []string{fmt.Sprintf("migration %d failed half way", version)}
+	}
+	if version != expected {
+		return []string{fmt.Sprintf("the database is at migration %d, expected %d", version, expected)}
+	}
+
+	return nil
+}
+
+func (c *HealthChecker) checkData(readiness *models.Readiness) []string {
+	updatedAt, err := c.store.LastUpdatedAt()
+	if err != nil {
+		c.logger.Errorf("Readiness check could not read the data age: %v", err)
+		return []string{"the data age could not be read"}
+	}
+
+	if updatedAt == nil {
+		return []string{"no ETF data was stored yet"}
+	}
+
+	age := time.Since(*updatedAt)
+	ageSeconds := int64(age / time.Second)
+
+	readiness.DataUpdatedAt = updatedAt
+	readiness.DataAgeSeconds = &ageSeconds
+
+	if age > c.maxDataAge {
+		return []string{fmt.Sprintf("the newest ETF data is %s old, more than %s", age.Round(time.Second), c.maxDataAge)}
+	}
+
+	return nil
+}
+
+// HealthzHandler function for the liveness probe, it succeeds while the process serves requests
+func (h Handlers) HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	WriteJSONResponse(w, map[string]string{"status": "ok"})
+}
+
+// ReadyzHandler function for the readiness probe, it responds with 503 when a check fails
+func (h Handlers) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	readiness := h.health.Readiness()
+
+	status := http.StatusOK
+	if !readiness.Ready {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSONStatus(w, status, readiness)
+}
@@ -0,0 +1,252 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sirupsen/logrus"
+
+	"awesomeProject/models"
+)
+
+// testPassword is the password of the users created by newTestAPI.
+const testPassword = "correct horse battery staple"
+
+// testRoles are the roles newTestAPI creates a user and a token for, the
+// username is the name of the role.
+var testRoles = []models.Role{models.RoleViewer, models.RoleAnalyst, models.RoleAdmin}
+
+// testAPI is the REST API backed by a MemoryStore with the ETFs of seedTestETFs.
+type testAPI struct {
+	t        *testing.T
+	store    *MemoryStore
+	server   *Server
+	updates  *UpdateBroker
+	keys     *KeySet
+	handlers *Handlers
+	handler  http.Handler
+	users    map[models.Role]*models.User
+	tokens   map[models.Role]string
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+
+	logger := newTestLogger()
+	store := NewMemoryStore()
+	seedTestETFs(t, store)
+
+	updates := NewUpdateBroker()
+	server := NewServer(logger, store, updates)
+	t.Cleanup(server.Close)
+
+	// The limits are tested on their own, here they would only get in the way
+	unlimited := RateLimit{Rate: 1000, Burst: 1000}
+	limits := RateLimitConfig{
+		Roles: map[models.Role]RateLimit{
+			models.RoleViewer:  unlimited,
+			models.RoleAnalyst: unlimited,
+			models.RoleAdmin:   unlimited,
+		},
+		Anonymous: unlimited,
+	}
+
+	keys := NewHMACKeySet([]byte("test secret that is only used by the tests"))
+	handlers := NewHandler(server, keys,
+		NewRateLimiter(limits, store, logger),
+		NewLoginThrottle(DefaultLoginThrottleConfig(), logger),
+		NewHealthChecker(store, nil, time.Hour, logger))
+
+	handler, err := MakeHTTPHandler(handlers)
+	if err != nil {
+		t.Fatalf("MakeHTTPHandler: %v", err)
+	}
+
+	api := &testAPI{
+		t:        t,
+		store:    store,
+		server:   server,
+		updates:  updates,
+		keys:     keys,
+		handlers: handlers,
+		handler:  handler,
+		users:    map[models.Role]*models.User{},
+		tokens:   map[models.Role]string{},
+	}
+
+	for _, role := range testRoles {
+		user, err := server.CreateUser(models.CreateUserRequest{Username: string(role), Password: testPassword, Role: role})
+		if err != nil {
+			t.Fatalf("CreateUser %s: %v", role, err)
+		}
+		api.users[role] = user
+		api.tokens[role] = api.login(string(role)).AccessToken
+	}
+
+	return api
+}
+
+// login logs the user in with testPassword.
+func (a *testAPI) login(username string) models.TokenResponse {
+	a.t.Helper()
+
+	rec := a.request(http.MethodPost, "/login", "", models.LoginRequest{Username: username, Password: testPassword})
+	if rec.Code != http.StatusOK {
+		a.t.Fatalf("login %s: status %d: %s", username, rec.Code, rec.Body)
+	}
+
+	var tokens models.TokenResponse
+	decodeTestJSON(a.t, rec, &tokens)
+	return tokens
+}
+
+// sessionID returns the session the access token was issued for.
+func (a *testAPI) sessionID(token string) string {
+	a.t.Helper()
+
+	var claims models.Claims
+	if _, err := jwt.ParseWithClaims(token, &claims, a.keys.Keyfunc); err != nil {
+		a.t.Fatalf("parse access token: %v", err)
+	}
+	return claims.SessionID
+}
+
+// do sends a request with the access token of role, an empty role sends none.
+func (a *testAPI) do(method, path string, role models.Role, body interface{}) *httptest.ResponseRecorder {
+	a.t.Helper()
+	return a.request(method, path, a.tokens[role], body)
+}
+
+// request sends a request with the access token, body is encoded as JSON unless it is nil.
+func (a *testAPI) request(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	a.t.Helper()
+
+	req := newTestRequest(a.t, method, path, body)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	a.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func newTestRequest(t *testing.T, method, path string, body interface{}) *http.Request {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("encode request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+func decodeTestJSON(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body, err)
+	}
+}
+
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// testETFs are the ETFs seedTestETFs stores.
+func testETFs() []models.ETFData {
+	nav := 512.34
+	aum := 513541.07e6
+	holdings := 503
+
+	return []models.ETFData{
+		{
+			Name:        "SPY",
+			Description: "The S&P 500 Index",
+			AssetClass:  models.AssetClassEquity,
+			Characteristics: &models.FundCharacteristics{
+				NAV:              &nav,
+				AUM:              &aum,
+				NumberOfHoldings: &holdings,
+				InceptionDate:    "1993-01-22",
+				Benchmark:        "S&P 500 Index",
+			},
+			TopHoldings: []models.Holding{
+				{Name: "Apple Inc.", SharesHeld: "170,000,000", Weight: "7.10%"},
+				{Name: "Microsoft Corporation", SharesHeld: "90,000,000", Weight: "6.90%"},
+			},
+			IndexTopHoldings: []models.Holding{
+				{Name: "Apple Inc.", Weight: "7.00%"},
+				{Name: "Microsoft Corporation", Weight: "7.05%"},
+			},
+			Countries: []models.WeightData{{Name: "United States", Weight: "99.50%"}},
+			Sectors:   []models.WeightData{{Name: "Information Technology", Weight: "29.00%"}},
+			Breakdowns: []models.Breakdown{
+				{Level: models.BreakdownSector, Weights: []models.WeightData{{Name: "Information Technology", Weight: "29.00%"}}},
+				{Level: models.BreakdownIndustry, Weights: []models.WeightData{{Name: "Software", Weight: "11.00%"}}},
+			},
+		},
+		{
+			Name:        "QQQ",
+			Description: "The Nasdaq-100 Index",
+			AssetClass:  models.AssetClassEquity,
+			TopHoldings: []models.Holding{
+				{Name: "Microsoft Corporation", SharesHeld: "60,000,000", Weight: "8.80%"},
+				{Name: "NVIDIA Corporation", SharesHeld: "30,000,000", Weight: "8.10%"},
+			},
+			Countries: []models.WeightData{{Name: "United States", Weight: "97.00%"}},
+			Sectors:   []models.WeightData{{Name: "Information Technology", Weight: "50.00%"}},
+		},
+	}
+}
+
+// seedTestETFs stores testETFs with full holdings for SPY and distributions for both.
+func seedTestETFs(t *testing.T, store Store) {
+	t.Helper()
+
+	var etfs []models.ETF
+	for _, etf := range testETFs() {
+		etfs = append(etfs, models.ETF{ID: etf.Name, Data: etf.ToJson()})
+	}
+	if err := store.UpsertMany(etfs); err != nil {
+		t.Fatalf("UpsertMany: %v", err)
+	}
+
+	weight := 7.1
+	if err := store.ReplaceHoldings([]models.FullHoldings{{
+		Ticker: "SPY",
+		AsOf:   "2026-10-16",
+		Holdings: []models.FullHolding{
+			{Name: "Apple Inc.", Ticker: "AAPL", Weight: &weight},
+			{Name: "Microsoft Corporation", Ticker: "MSFT"},
+			{Name: "NVIDIA Corporation", Ticker: "NVDA"},
+		},
+	}}); err != nil {
+		t.Fatalf("ReplaceHoldings: %v", err)
+	}
+
+	recent := time.Now().AddDate(0, -1, 0).Format("2006-01-02")
+	if err := store.UpsertDistributions([]models.ETFDistributions{
+		{Ticker: "SPY", Distributions: []models.Distribution{{ExDate: recent, Amount: 1.75}, {ExDate: "2024-03-15", Amount: 1.59}}},
+		{Ticker: "QQQ", Distributions: []models.Distribution{{ExDate: recent, Amount: 0.71}}},
+	}); err != nil {
+		t.Fatalf("UpsertDistributions: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/xuri/excelize/v2"
+
+	"awesomeProject/models"
+)
+
+// holdingsFileSelector finds the link to the daily holdings spreadsheet on a fund page.
+const holdingsFileSelector = "a[href*='holdings-daily'][href$='.xlsx']"
+
+// holdingColumnLabels lists the header cells each column of the holdings
+// spreadsheet is shown with, normalized by normalizeLabel.
+var holdingColumnLabels = map[string][]string{
+	"name":         {"name", "security name", "holding name"},
+	"ticker":       {"ticker", "symbol"},
+	"identifier":   {"identifier", "cusip", "isin"},
+	"sedol":        {"sedol"},
+	"sector":       {"sector"},
+	"shares_held":  {"shares held", "shares", "quantity", "par value"},
+	"market_value": {"market value", "market value (usd)", "notional value"},
+	"weight":       {"weight", "% weight", "weight (%)"},
+	"currency":     {"local currency", "currency"},
+}
+
+// findHoldingsFileURL returns the absolute URL of the daily holdings
+// spreadsheet linked on the fund page, or an empty string when there is none.
+func (u *DailyDataUpdater) findHoldingsFileURL(doc *goquery.Document) string {
+	href, ok := doc.Find(holdingsFileSelector).First().Attr("href")
+	if !ok || strings.TrimSpace(href) == "" {
+		return ""
+	}
+
+	link, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return ""
+	}
+
+	base, err := url.Parse(u.host)
+	if err != nil {
+		return link.String()
+	}
+
+	return base.ResolveReference(link).String()
+}
+
+// FetchHoldings downloads the holdings spreadsheet at url and parses it without storing the result.
+func (u *DailyDataUpdater) FetchHoldings(url, ticker string) (*models.FullHoldings, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return u.ParseHoldings(resp.Body, ticker)
+}
+
+// ParseHoldings parses the daily holdings spreadsheet of the ETF ticker. The
+// positions are read from the first sheet below the header row naming the
+// columns, until the first empty row which starts the footnotes.
+func (u *DailyDataUpdater) ParseHoldings(r io.Reader, ticker string) (*models.FullHoldings, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows(f.GetSheetName(0))
+	if err != nil {
+		return nil, err
+	}
+
+	holdings := models.FullHoldings{Ticker: ticker}
+
+	header := -1
+	var columns map[string]int
+
+	for i, row := range rows {
+		// The rows above the header describe the file, e.g. "Holdings: | As of 17-Oct-2026"
+		if date, ok := findAsOfDate(row); ok && holdings.AsOf == "" {
+			holdings.AsOf = date
+		}
+
+		if columns = findHoldingColumns(row); columns != nil {
+			header = i
+			break
+		}
+	}
+
+	if header < 0 {
+		return nil, errors.New("holdings file has no header row")
+	}
+
+	cell := func(row []string, key string) string {
+		index, ok := columns[key]
+		if !ok || index >= len(row) {
+			return ""
+		}
+		return strings.Join(strings.Fields(row[index]), " ")
+	}
+	number := func(row []string, key string) *float64 {
+		if n, ok := parseNumber(cell(row, key)); ok {
+			return &n
+		}
+		return nil
+	}
+
+	for _, row := range rows[header+1:] {
+		name := cell(row, "name")
+		if name == "" {
+			break
+		}
+
+		holdings.Holdings = append(holdings.Holdings, models.FullHolding{
+			Name:        name,
+			Ticker:      strings.Trim(cell(row, "ticker"), "-"),
+			Identifier:  strings.Trim(cell(row, "identifier"), "-"),
+			SEDOL:       strings.Trim(cell(row, "sedol"), "-"),
+			Sector:      strings.Trim(cell(row, "sector"), "-"),
+			SharesHeld:  number(row, "shares_held"),
+			MarketValue: number(row, "market_value"),
+			Weight:      number(row, "weight"),
+			Currency:    strings.Trim(cell(row, "currency"), "-"),
+		})
+	}
+
+	if len(holdings.Holdings) == 0 {
+		return nil, errors.New("holdings file lists no positions")
+	}
+
+	return &holdings, nil
+}
+
+// findHoldingColumns maps the column keys of holdingColumnLabels to their index
+// when row is the header row, which names at least the holding and its weight.
+func findHoldingColumns(row []string) map[string]int {
+	columns := map[string]int{}
+	for i, cell := range row {
+		label := normalizeLabel(cell)
+		for key, labels := range holdingColumnLabels {
+			if _, ok := columns[key]; ok {
+				continue
+			}
+			for _, candidate := range labels {
+				if label == candidate {
+					columns[key] = i
+					break
+				}
+			}
+		}
+	}
+
+	_, hasName := columns["name"]
+	_, hasWeight := columns["weight"]
+	if !hasName || !hasWeight {
+		return nil
+	}
+
+	return columns
+}
+
+// findAsOfDate finds a date such as "As of 17-Oct-2026" in a row above the header.
+func findAsOfDate(row []string) (string, bool) {
+	for _, cell := range row {
+		cell = strings.TrimSpace(cell)
+		if !strings.HasPrefix(strings.ToLower(cell), "as of") {
+			continue
+		}
+		if date, ok := parseDate(strings.TrimSpace(cell[len("as of"):])); ok {
+			return date, true
+		}
+	}
+
+	return "", false
+}
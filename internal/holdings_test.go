@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"awesomeProject/models"
+)
+
+func TestParseHoldings(t *testing.T) {
+	// The file starts with the fund name, ticker and date above the header
+	// row, and ends with an empty row followed by disclaimers.
+	file, err := os.Open(filepath.Join("testdata", "holdings.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	updater := NewDailyDataUpdater("", nil, nil, DefaultUpdaterConfig(), newTestLogger())
+	holdings, err := updater.ParseHoldings(file, "SPY")
+	if err != nil {
+		t.Fatalf("ParseHoldings: %v", err)
+	}
+
+	if holdings.Ticker != "SPY" || holdings.AsOf != "2026-10-16" {
+		t.Errorf("ticker %q, as of %q", holdings.Ticker, holdings.AsOf)
+	}
+	if len(holdings.Holdings) != 5 {
+		t.Fatalf("%d positions, want the 5 above the footnotes: %+v", len(holdings.Holdings), holdings.Holdings)
+	}
+
+	number := func(n float64) *float64 { return &n }
+
+	tests := []struct {
+		index int
+		want  models.FullHolding
+	}{
+		{0, models.FullHolding{
+			Name: "APPLE INC", Ticker: "AAPL", Identifier: "037833100", SEDOL: "2046251",
+			Sector: "Information Technology", SharesHeld: number(172312530),
+			MarketValue: number(39112045310.40), Weight: number(7.123456), Currency: "USD",
+		}},
+		{3, models.FullHolding{
+			Name: "BERKSHIRE HATHAWAY INC CL B", Ticker: "BRK.B", Identifier: "084670702", SEDOL: "2073390",
+			Sector: "Financials", SharesHeld: number(21990341),
+			MarketValue: number(9402311402.12), Weight: number(1.71), Currency: "USD",
+		}},
+		// Placeholders are dropped
+		{4, models.FullHolding{
+			Name: "US DOLLAR", SharesHeld: number(27431902),
+			MarketValue: number(27431902), Weight: number(0.05), Currency: "USD",
+		}},
+	}
+
+	for _, test := range tests {
+		got := holdings.Holdings[test.index]
+		if got.Name != test.want.Name || got.Ticker != test.want.Ticker || got.Identifier != test.want.Identifier ||
+			got.SEDOL != test.want.SEDOL || got.Sector != test.want.Sector || got.Currency != test.want.Currency ||
+			!equalNumber(got.SharesHeld, test.want.SharesHeld) || !equalNumber(got.MarketValue, test.want.MarketValue) ||
+			!equalNumber(got.Weight, test.want.Weight) {
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(test.want)
+			t.Errorf("position %d = %s, want %s", test.index, gotJSON, wantJSON)
+		}
+	}
+}
+
+func TestFindHoldingColumns(t *testing.T) {
+	for _, row := range [][]string{
+		{"Fund Name:", "SPDR® S&P 500® ETF Trust"},
+		{"Holdings:", "As of 16-Oct-2026"},
+		{"Name", "Ticker", "Shares Held"},
+		{},
+	} {
+		if columns := findHoldingColumns(row); columns != nil {
+			t.Errorf("findHoldingColumns(%q) = %v, want no header", row, columns)
+		}
+	}
+
+	columns := findHoldingColumns([]string{"Security Name", "CUSIP", "% Weight", "Par Value", "Market Value (USD)"})
+	want := map[string]int{"name": 0, "identifier": 1, "weight": 2, "shares_held": 3, "market_value": 4}
+	if len(columns) != len(want) {
+		t.Fatalf("columns = %v, want %v", columns, want)
+	}
+	for key, index := range want {
+		if columns[key] != index {
+			t.Errorf("column %s = %d, want %d", key, columns[key], index)
+		}
+	}
+}
+
+func equalNumber(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -4,19 +4,79 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+
+	"awesomeProject/models"
 )
 
-func MakeHTTPHandler(h *Handlers) http.Handler {
+// MakeHTTPHandler builds the router of the REST API. It fails when the
+// OpenAPI document requests are validated against is invalid.
+func MakeHTTPHandler(h *Handlers) (http.Handler, error) {
+	validateRequests, err := ValidateRequests()
+	if err != nil {
+		return nil, err
+	}
+
 	r := mux.NewRouter()
+	r.Use(RequestID, validateRequests)
 
 	// Use the requireTokenAuthentication middleware for routes that require authentication
 	secured := r.PathPrefix("/secured").Subrouter()
-	secured.Use(h.RequireTokenAuthentication)
+	secured.Use(h.RequireTokenAuthentication, h.limiter.Limit, h.Audit(models.AuditRequest))
 
+	// Read endpoints are available to every role
 	secured.HandleFunc("/etfs", h.ListETFSymbolsHandler).Methods("GET")
 	secured.HandleFunc("/etf/{ticker}", h.GetETFDataHandler).Methods("GET")
+	secured.HandleFunc("/etf/{ticker}/holdings", h.GetHoldingsHandler).Methods("GET")
+	secured.HandleFunc("/etf/{ticker}/breakdowns", h.GetBreakdownsHandler).Methods("GET")
+	secured.HandleFunc("/etf/{ticker}/distributions", h.GetDistributionsHandler).Methods("GET")
+	secured.HandleFunc("/me/password", h.ChangePasswordHandler).Methods("PUT")
+	secured.HandleFunc("/me/sessions", h.ListSessionsHandler).Methods("GET")
+	secured.HandleFunc("/me/sessions", h.RevokeAllSessionsHandler).Methods("DELETE")
+	secured.HandleFunc("/me/sessions/{id}", h.RevokeSessionHandler).Methods("DELETE")
+	secured.HandleFunc("/me/apikeys", h.ListAPIKeysHandler).Methods("GET")
+	secured.HandleFunc("/me/apikeys", h.CreateAPIKeyHandler).Methods("POST")
+	secured.HandleFunc("/me/apikeys/{id:[0-9]+}", h.RevokeAPIKeyHandler).Methods("DELETE")
+
+	// Export and analytics endpoints need at least the analyst role
+	analytics := secured.NewRoute().Subrouter()
+	analytics.Use(h.RequireRole(models.RoleAnalyst))
+
+	analytics.HandleFunc("/export", h.ExportETFsHandler).Methods("GET")
+	analytics.HandleFunc("/overlap", h.OverlapHandler).Methods("GET")
+	analytics.HandleFunc("/exposure", h.ExposureHandler).Methods("POST")
+	analytics.HandleFunc("/etf/{ticker}/tracking", h.TrackingHandler).Methods("GET")
+
+	// GraphQL queries over the ETF data are available to every role, the
+	// resolvers reserve queries over many ETFs to analysts
+	graphQL := r.PathPrefix("/graphql").Subrouter()
+	graphQL.Use(h.RequireTokenAuthentication, h.limiter.Limit, h.Audit(models.AuditRequest))
+
+	graphQL.HandleFunc("", h.GraphQLHandler).Methods("GET", "POST")
+
+	// User management is only available to admins
+	admin := r.PathPrefix("/admin").Subrouter()
+	admin.Use(h.RequireTokenAuthentication, h.limiter.Limit, h.Audit(models.AuditAdminAction), h.RequireRole(models.RoleAdmin))
+
+	admin.HandleFunc("/users", h.ListUsersHandler).Methods("GET")
+	admin.HandleFunc("/users", h.CreateUserHandler).Methods("POST")
+	admin.HandleFunc("/users/{id:[0-9]+}", h.DeleteUserHandler).Methods("DELETE")
+	admin.HandleFunc("/users/{id:[0-9]+}/disable", h.DisableUserHandler).Methods("POST")
+	admin.HandleFunc("/users/{id:[0-9]+}/enable", h.EnableUserHandler).Methods("POST")
+	admin.HandleFunc("/users/{id:[0-9]+}/password", h.ResetPasswordHandler).Methods("PUT")
+	admin.HandleFunc("/users/{id:[0-9]+}/role", h.SetUserRoleHandler).Methods("PUT")
+	admin.HandleFunc("/audit", h.AuditLogHandler).Methods("GET")
+
+	// Unauthenticated endpoints are limited per client IP
+	r.Handle("/login", h.limiter.LimitAnonymous(http.HandlerFunc(h.LoginHandler))).Methods("POST")
+	r.Handle("/token/refresh", h.limiter.LimitAnonymous(http.HandlerFunc(h.RefreshTokenHandler))).Methods("POST")
+	r.HandleFunc("/.well-known/jwks.json", h.JWKSHandler).Methods("GET")
+	r.HandleFunc("/openapi.json", h.OpenAPIHandler).Methods("GET")
+	r.HandleFunc("/docs", h.SwaggerUIHandler).Methods("GET")
 
-	r.HandleFunc("/login", h.LoginHandler).Methods("POST")
+	// Probes of the orchestrator are neither authenticated nor limited
+	r.HandleFunc("/healthz", h.HealthzHandler).Methods("GET")
+	r.HandleFunc("/readyz", h.ReadyzHandler).Methods("GET")
+	r.Handle("/logout", h.RequireTokenAuthentication(http.HandlerFunc(h.LogoutHandler))).Methods("POST")
 
-	return r
+	return r, nil
 }
@@ -0,0 +1,341 @@
+package internal
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// hmacKeyID identifies the shared secret key. Tokens signed with it carry no kid header.
+const hmacKeyID = ""
+
+// insecureJWTSecret is the secret older versions used when JWT_SECRET was not
+// set. It is public, so tokens signed with it can be forged by anyone.
+const insecureJWTSecret = "something"
+
+// minJWTSecretLength is the minimum length of JWT_SECRET in bytes.
+const minJWTSecretLength = 32
+
+// SigningKey is a key used to sign and verify access tokens.
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	// signer is nil for keys that may only verify tokens
+	signer interface{}
+	// verifier is the public key, or the secret for HMAC keys
+	verifier interface{}
+	// notAfter is the time after which tokens are no longer verified with the key, zero means never
+	notAfter time.Time
+}
+
+// KeySet holds every key access tokens are verified with and the one new
+// tokens are signed with. Keeping retired keys in the set lets tokens signed
+// with them stay valid until they expire.
+type KeySet struct {
+	mu       sync.RWMutex
+	dir      string
+	activeID string
+	secret   []byte
+	active   *SigningKey
+	keys     map[string]*SigningKey
+
+	// secretUntil is the time until which tokens signed with secret stay valid
+	secretUntil time.Time
+}
+
+// NewHMACKeySet creates a key set that signs and verifies HS256 tokens with a shared secret.
+func NewHMACKeySet(secret []byte) *KeySet {
+	key := hmacKey(secret)
+	return &KeySet{
+		secret: secret,
+		active: key,
+		keys:   map[string]*SigningKey{hmacKeyID: key},
+	}
+}
+
+// LoadKeySet loads the PEM encoded keys in dir. The file name without the
+// extension is used as the kid. Private RSA and ECDSA keys can sign and
+// verify, public keys can only verify. New tokens are signed with the key
+// activeID. A non-empty secret keeps HS256 tokens issued before the switch
+// to asymmetric keys valid until secretUntil; with a zero secretUntil the
+// secret is ignored.
+func LoadKeySet(dir, activeID string, secret []byte, secretUntil time.Time) (*KeySet, error) {
+	k := &KeySet{
+		dir:         dir,
+		activeID:    activeID,
+		secret:      secret,
+		secretUntil: secretUntil,
+	}
+
+	if err := k.Reload(); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// Reload reads the key directory again, so keys can be rotated without a restart.
+func (k *KeySet) Reload() error {
+	if k.dir == "" {
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(k.dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]*SigningKey{}
+	if len(k.secret) > 0 && time.Now().Before(k.secretUntil) {
+		key := hmacKey(k.secret)
+		// The secret only verifies old tokens, new ones are signed with the active key
+		key.signer = nil
+		key.notAfter = k.secretUntil
+		keys[hmacKeyID] = key
+	}
+
+	for _, file := range files {
+		id := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+
+		key, err := loadKey(id, file)
+		if err != nil {
+			return fmt.Errorf("could not load key %s: %w", file, err)
+		}
+		keys[id] = key
+	}
+
+	active, ok := keys[k.activeID]
+	if !ok || k.activeID == hmacKeyID {
+		return fmt.Errorf("active key %q not found in %s", k.activeID, k.dir)
+	}
+
+	if active.signer == nil {
+		return fmt.Errorf("active key %q has no private key", k.activeID)
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.active = active
+	k.mu.Unlock()
+
+	return nil
+}
+
+// KeySet loads the configured signing keys. Without JWTKeysDir tokens are
+// signed with JWTSecret, which then has to be set to a long random value.
+func (c Config) KeySet() (*KeySet, error) {
+	if c.JWTKeysDir == "" {
+		if err := checkJWTSecret(c.JWTSecret); err != nil {
+			return nil, err
+		}
+		return NewHMACKeySet([]byte(c.JWTSecret)), nil
+	}
+
+	var secret []byte
+	if !c.JWTSecretValidUntil.IsZero() {
+		if err := checkJWTSecret(c.JWTSecret); err != nil {
+			return nil, err
+		}
+		secret = []byte(c.JWTSecret)
+	}
+
+	return LoadKeySet(c.JWTKeysDir, c.JWTActiveKeyID, secret, c.JWTSecretValidUntil)
+}
+
+// checkJWTSecret rejects secrets that are missing, public or too short to resist guessing.
+func checkJWTSecret(secret string) error {
+	switch {
+	case secret == "":
+		return errors.New("JWT_SECRET is not set")
+	case secret == insecureJWTSecret:
+		return errors.New("JWT_SECRET still has the insecure default value")
+	case len(secret) < minJWTSecretLength:
+		return fmt.Errorf("JWT_SECRET must be at least %d bytes long", minJWTSecretLength)
+	}
+	return nil
+}
+
+// Sign signs the claims with the active key and sets the kid header.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	k.mu.RLock()
+	active := k.active
+	k.mu.RUnlock()
+
+	token := jwt.NewWithClaims(active.Method, claims)
+	if active.ID != hmacKeyID {
+		token.Header["kid"] = active.ID
+	}
+
+	return token.SignedString(active.signer)
+}
+
+// Keyfunc returns the key a token has to be verified with. The algorithm of
+// the token has to match the key, otherwise e.g. a public key could be
+// misused as HMAC secret.
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	id, _ := token.Header["kid"].(string)
+
+	k.mu.RLock()
+	key, ok := k.keys[id]
+	k.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", id)
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), id)
+	}
+
+	if !key.notAfter.IsZero() && time.Now().After(key.notAfter) {
+		return nil, fmt.Errorf("key %q is no longer accepted", id)
+	}
+
+	return key.verifier, nil
+}
+
+// JWKS returns the public keys of the set as a JSON Web Key Set.
+// Shared secrets are never published.
+func (k *KeySet) JWKS() JSONWebKeySet {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, key := range k.keys {
+		if jwk, ok := key.jwk(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].KeyID < set.Keys[j].KeyID
+	})
+
+	return set
+}
+
+// JSONWebKeySet is the document served at /.well-known/jwks.json.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JSONWebKey is a public key as described in RFC 7517.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+func (key *SigningKey) jwk() (JSONWebKey, bool) {
+	jwk := JSONWebKey{
+		KeyID:     key.ID,
+		Use:       "sig",
+		Algorithm: key.Method.Alg(),
+	}
+
+	switch pub := key.verifier.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = pub.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+	default:
+		return JSONWebKey{}, false
+	}
+
+	return jwk, true
+}
+
+func hmacKey(secret []byte) *SigningKey {
+	return &SigningKey{
+		ID:       hmacKeyID,
+		Method:   jwt.SigningMethodHS256,
+		signer:   secret,
+		verifier: secret,
+	}
+}
+
+// loadKey reads a PEM encoded RSA or ECDSA key. Private keys may be PKCS #1,
+// SEC 1 or PKCS #8 encoded, public keys PKIX encoded.
+func loadKey(id, file string) (*SigningKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &SigningKey{ID: id}
+
+	if signer, ok := parsed.(crypto.Signer); ok {
+		key.signer = signer
+		parsed = signer.Public()
+	}
+
+	switch pub := parsed.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			key.Method = jwt.SigningMethodES256
+		case elliptic.P384():
+			key.Method = jwt.SigningMethodES384
+		case elliptic.P521():
+			key.Method = jwt.SigningMethodES512
+		default:
+			return nil, errors.New("unsupported elliptic curve")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+	key.verifier = parsed
+
+	return key, nil
+}
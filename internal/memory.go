@@ -0,0 +1,738 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"awesomeProject/models"
+)
+
+// MemoryStore keeps everything in maps. It needs no database, which makes it
+// the store of choice for unit tests of the handlers and the updater.
+// Values are copied in and out, callers can not change the stored records.
+type MemoryStore struct {
+	mu sync.Mutex
+
+	etfs     map[string]models.ETF
+	holdings map[string]models.FullHoldings
+	users    map[int]models.User
+	sessions map[string]models.Session
+	apiKeys  map[int]models.APIKey
+	quotas   map[string]map[string]int
+	audit    []models.AuditEvent
+
+	// distributions maps the ETF ID to its distributions by ex-date and type
+	distributions map[string]map[string]models.Distribution
+
+	lastUserID   int
+	lastAPIKeyID int
+}
+
+// NewMemoryStore creates an empty store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		etfs:     make(map[string]models.ETF),
+		holdings: make(map[string]models.FullHoldings),
+		users:    make(map[int]models.User),
+		sessions: make(map[string]models.Session),
+		apiKeys:  make(map[int]models.APIKey),
+		quotas:   make(map[string]map[string]int),
+
+		distributions: make(map[string]map[string]models.Distribution),
+	}
+}
+
+// Upsert either updates an existing ETF record or creates a new one.
+func (m *MemoryStore) Upsert(etf models.ETF) error {
+	return m.UpsertMany([]models.ETF{etf})
+}
+
+// UpsertMany updates or creates all ETF records at once.
+func (m *MemoryStore) UpsertMany(etfs []models.ETF) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.upsertETFs(etfs)
+
+	return nil
+}
+
+// upsertETFs runs UpsertMany, the caller has to hold the lock.
+func (m *MemoryStore) upsertETFs(etfs []models.ETF) {
+	now := time.Now()
+
+	for _, etf := range etfs {
+		if existing, ok := m.etfs[etf.ID]; ok {
+			etf.CreatedAt = existing.CreatedAt
+		} else {
+			etf.CreatedAt = now
+		}
+		etf.UpdatedAt = now
+		etf.Data = append([]byte(nil), etf.Data...)
+
+		m.etfs[etf.ID] = etf
+	}
+}
+
+// GetAllIDs retrieves all available ETF IDs.
+func (m *MemoryStore) GetAllIDs() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ids []string
+	for id := range m.etfs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// GetAll retrieves all ETFs ordered by ID.
+func (m *MemoryStore) GetAll() ([]models.ETF, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var etfs []models.ETF
+	for _, etf := range m.etfs {
+		etf.Data = append([]byte(nil), etf.Data...)
+		etfs = append(etfs, etf)
+	}
+	sort.Slice(etfs, func(i, j int) bool { return etfs[i].ID < etfs[j].ID })
+
+	return etfs, nil
+}
+
+// GetByID retrieves an ETF by its ID.
+func (m *MemoryStore) GetByID(id string) (*models.ETF, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	etf, ok := m.etfs[id]
+	if !ok {
+		return nil, fmt.Errorf("ETF %w", ErrNotFound)
+	}
+	etf.Data = append([]byte(nil), etf.Data...)
+
+	return &etf, nil
+}
+
+// LastUpdatedAt returns when the most recently updated ETF was stored, nil when there is none.
+func (m *MemoryStore) LastUpdatedAt() (*time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var last *time.Time
+	for _, etf := range m.etfs {
+		if last == nil || etf.UpdatedAt.After(*last) {
+			updatedAt := etf.UpdatedAt
+			last = &updatedAt
+		}
+	}
+
+	return last, nil
+}
+
+// ReplaceHoldings stores the full holdings of every ETF at once.
+func (m *MemoryStore) ReplaceHoldings(holdings []models.FullHoldings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, etfHoldings := range holdings {
+		if _, ok := m.etfs[etfHoldings.Ticker]; !ok {
+			return fmt.Errorf("ETF %s %w", etfHoldings.Ticker, ErrNotFound)
+		}
+	}
+
+	m.replaceHoldings(holdings)
+
+	return nil
+}
+
+// replaceHoldings runs ReplaceHoldings once the ETFs were checked, the caller has to hold the lock.
+func (m *MemoryStore) replaceHoldings(holdings []models.FullHoldings) {
+	for _, etfHoldings := range holdings {
+		etfHoldings.Holdings = append([]models.FullHolding(nil), etfHoldings.Holdings...)
+		m.holdings[etfHoldings.Ticker] = etfHoldings
+	}
+}
+
+// ListHoldings returns a page of the full holdings of an ETF.
+func (m *MemoryStore) ListHoldings(etfID string, limit, offset int) (*models.HoldingsPage, error) {
+	if limit < 0 || offset < 0 {
+		return nil, fmt.Errorf("%w: negative limit or offset", ErrInvalidInput)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	etfHoldings, ok := m.holdings[etfID]
+	if !ok || len(etfHoldings.Holdings) == 0 {
+		return nil, fmt.Errorf("holdings %w", ErrNotFound)
+	}
+
+	page := models.HoldingsPage{
+		Ticker:   etfID,
+		AsOf:     etfHoldings.AsOf,
+		Total:    len(etfHoldings.Holdings),
+		Holdings: []models.FullHolding{},
+	}
+	if offset < len(etfHoldings.Holdings) {
+		end := offset + limit
+		if end > len(etfHoldings.Holdings) {
+			end = len(etfHoldings.Holdings)
+		}
+		page.Holdings = append(page.Holdings, etfHoldings.Holdings[offset:end]...)
+	}
+
+	return &page, nil
+}
+
+// UpsertDistributions stores the distributions of every ETF at once.
+func (m *MemoryStore) UpsertDistributions(distributions []models.ETFDistributions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, etfDistributions := range distributions {
+		if _, ok := m.etfs[etfDistributions.Ticker]; !ok {
+			return fmt.Errorf("ETF %s %w", etfDistributions.Ticker, ErrNotFound)
+		}
+	}
+
+	m.upsertDistributions(distributions)
+
+	return nil
+}
+
+// upsertDistributions runs UpsertDistributions once the ETFs were checked, the caller has to hold the lock.
+func (m *MemoryStore) upsertDistributions(distributions []models.ETFDistributions) {
+	for _, etfDistributions := range distributions {
+		stored, ok := m.distributions[etfDistributions.Ticker]
+		if !ok {
+			stored = make(map[string]models.Distribution)
+			m.distributions[etfDistributions.Ticker] = stored
+		}
+
+		for _, d := range etfDistributions.Distributions {
+			stored[d.ExDate+"|"+d.Type] = d
+		}
+	}
+}
+
+// StoreScraped stores the ETF records, their full holdings and their distributions at once.
+func (m *MemoryStore) StoreScraped(etfs []models.ETF, holdings []models.FullHoldings, distributions []models.ETFDistributions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := make(map[string]bool, len(etfs))
+	for _, etf := range etfs {
+		stored[etf.ID] = true
+	}
+
+	var tickers []string
+	for _, etfHoldings := range holdings {
+		tickers = append(tickers, etfHoldings.Ticker)
+	}
+	for _, etfDistributions := range distributions {
+		tickers = append(tickers, etfDistributions.Ticker)
+	}
+	for _, ticker := range tickers {
+		if _, ok := m.etfs[ticker]; !ok && !stored[ticker] {
+			return fmt.Errorf("ETF %s %w", ticker, ErrNotFound)
+		}
+	}
+
+	m.upsertETFs(etfs)
+	m.replaceHoldings(holdings)
+	m.upsertDistributions(distributions)
+
+	return nil
+}
+
+// ListDistributions returns the distributions of an ETF within a date range.
+func (m *MemoryStore) ListDistributions(etfID, from, to string) ([]models.Distribution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	distributions := []models.Distribution{}
+	for _, d := range m.distributions[etfID] {
+		if from != "" && d.ExDate < from || to != "" && d.ExDate > to {
+			continue
+		}
+		distributions = append(distributions, d)
+	}
+
+	sort.Slice(distributions, func(i, j int) bool {
+		if distributions[i].ExDate != distributions[j].ExDate {
+			return distributions[i].ExDate > distributions[j].ExDate
+		}
+		return distributions[i].Type < distributions[j].Type
+	})
+
+	return distributions, nil
+}
+
+// Ping always succeeds, the store lives in the process.
+func (m *MemoryStore) Ping() error {
+	return nil
+}
+
+// CreateUser inserts a new user and returns it with the generated fields set.
+func (m *MemoryStore) CreateUser(user models.User) (*models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.users {
+		if existing.Username == user.Username {
+			return nil, fmt.Errorf("user %w", ErrAlreadyExists)
+		}
+	}
+
+	m.lastUserID++
+	now := time.Now()
+
+	user.ID = m.lastUserID
+	user.LastLoginAt = nil
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	m.users[user.ID] = user
+	return &user, nil
+}
+
+// ListUsers retrieves all users ordered by ID.
+func (m *MemoryStore) ListUsers() ([]models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	users := []models.User{}
+	for _, user := range m.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	return users, nil
+}
+
+// GetUserByID retrieves a user by ID.
+func (m *MemoryStore) GetUserByID(id int) (*models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user %w", ErrNotFound)
+	}
+
+	return &user, nil
+}
+
+// GetUserByUsername retrieves a user by username.
+func (m *MemoryStore) GetUserByUsername(username string) (*models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, user := range m.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+
+	return nil, fmt.Errorf("user %w", ErrNotFound)
+}
+
+// UpdateUserPassword replaces the stored password hash of a user.
+func (m *MemoryStore) UpdateUserPassword(id int, password string) error {
+	return m.updateUser(id, func(user *models.User) {
+		user.Password = password
+		user.UpdatedAt = time.Now()
+	})
+}
+
+// ReplaceUserPassword replaces the stored password hash of a user while it still is current.
+func (m *MemoryStore) ReplaceUserPassword(id int, current, password string) (bool, error) {
+	replaced := false
+	err := m.updateUser(id, func(user *models.User) {
+		if user.Password == current {
+			user.Password = password
+			user.UpdatedAt = time.Now()
+			replaced = true
+		}
+	})
+	return replaced, err
+}
+
+// SetUserDisabled enables or disables a user.
+func (m *MemoryStore) SetUserDisabled(id int, disabled bool) error {
+	return m.updateUser(id, func(user *models.User) {
+		user.Disabled = disabled
+		user.UpdatedAt = time.Now()
+	})
+}
+
+// SetUserRole changes the role of a user.
+func (m *MemoryStore) SetUserRole(id int, role models.Role) error {
+	return m.updateUser(id, func(user *models.User) {
+		user.Role = role
+		user.UpdatedAt = time.Now()
+	})
+}
+
+// UpdateLastLogin sets the last login time of a user to now.
+func (m *MemoryStore) UpdateLastLogin(id int) error {
+	return m.updateUser(id, func(user *models.User) {
+		now := time.Now()
+		user.LastLoginAt = &now
+	})
+}
+
+// DeleteUser removes a user together with their sessions and API keys.
+func (m *MemoryStore) DeleteUser(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[id]; !ok {
+		return fmt.Errorf("user %w", ErrNotFound)
+	}
+
+	delete(m.users, id)
+
+	// Mirror ON DELETE CASCADE of the SQL stores
+	for sessionID, session := range m.sessions {
+		if session.UserID == id {
+			delete(m.sessions, sessionID)
+		}
+	}
+	for keyID, key := range m.apiKeys {
+		if key.UserID == id {
+			delete(m.apiKeys, keyID)
+		}
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) updateUser(id int, update func(user *models.User)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.users[id]
+	if !ok {
+		return fmt.Errorf("user %w", ErrNotFound)
+	}
+
+	update(&user)
+	m.users[id] = user
+
+	return nil
+}
+
+// CreateSession stores a new session that expires after the given TTL.
+func (m *MemoryStore) CreateSession(session models.Session, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[session.ID]; ok {
+		return fmt.Errorf("session %w", ErrAlreadyExists)
+	}
+
+	now := time.Now()
+
+	session.Current = false
+	session.CreatedAt = now
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(ttl)
+	session.RevokedAt = nil
+
+	m.sessions[session.ID] = session
+	return nil
+}
+
+// RotateRefreshToken replaces the refresh token hash of an active session and extends it by the TTL.
+// It returns false when the session is unknown, revoked, expired or the old hash does not match.
+func (m *MemoryStore) RotateRefreshToken(id, oldHash, newHash string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	session, ok := m.sessions[id]
+	if !ok || session.RefreshTokenHash != oldHash || !sessionActive(session, now) {
+		return false, nil
+	}
+
+	session.RefreshTokenHash = newHash
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(ttl)
+	m.sessions[id] = session
+
+	return true, nil
+}
+
+// GetSession retrieves a session by ID.
+func (m *MemoryStore) GetSession(id string) (*models.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("session %w", ErrNotFound)
+	}
+
+	return &session, nil
+}
+
+// ListActiveSessions retrieves the sessions of a user that are neither revoked nor expired.
+func (m *MemoryStore) ListActiveSessions(userID int) ([]models.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	sessions := []models.Session{}
+	for _, session := range m.sessions {
+		if session.UserID == userID && sessionActive(session, now) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt) })
+
+	return sessions, nil
+}
+
+// IsSessionActive reports whether the session is neither revoked nor expired and its user is enabled.
+func (m *MemoryStore) IsSessionActive(id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[id]
+	if !ok || !sessionActive(session, time.Now()) {
+		return false, nil
+	}
+
+	user, ok := m.users[session.UserID]
+	return ok && !user.Disabled, nil
+}
+
+// RevokeSession revokes a single session of a user.
+func (m *MemoryStore) RevokeSession(userID int, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[id]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return fmt.Errorf("session %w", ErrNotFound)
+	}
+
+	now := time.Now()
+	session.RevokedAt = &now
+	m.sessions[id] = session
+
+	return nil
+}
+
+// RevokeUserSessions revokes every session of a user except the one with the given ID.
+// Pass an empty ID to revoke all of them.
+func (m *MemoryStore) RevokeUserSessions(userID int, exceptID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	for id, session := range m.sessions {
+		if session.UserID == userID && id != exceptID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+			m.sessions[id] = session
+		}
+	}
+
+	return nil
+}
+
+func sessionActive(session models.Session, now time.Time) bool {
+	return session.RevokedAt == nil && session.ExpiresAt.After(now)
+}
+
+// CreateAPIKey stores a new API key. A zero TTL creates a key that never expires.
+func (m *MemoryStore) CreateAPIKey(key models.APIKey, ttl time.Duration) (*models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[key.UserID]; !ok {
+		return nil, fmt.Errorf("user %w", ErrNotFound)
+	}
+
+	for _, existing := range m.apiKeys {
+		if existing.Prefix == key.Prefix {
+			return nil, fmt.Errorf("api key %w", ErrAlreadyExists)
+		}
+	}
+
+	m.lastAPIKeyID++
+	now := time.Now()
+
+	key.ID = m.lastAPIKeyID
+	key.CreatedAt = now
+	key.ExpiresAt = nil
+	key.LastUsedAt = nil
+	key.RevokedAt = nil
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		key.ExpiresAt = &expiresAt
+	}
+
+	m.apiKeys[key.ID] = key
+	return &key, nil
+}
+
+// ListAPIKeys retrieves all API keys of a user, including revoked and expired ones.
+func (m *MemoryStore) ListAPIKeys(userID int) ([]models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := []models.APIKey{}
+	for _, key := range m.apiKeys {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys, nil
+}
+
+// GetActiveAPIKey retrieves an API key by its prefix if it is neither revoked nor expired.
+func (m *MemoryStore) GetActiveAPIKey(prefix string) (*models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	for _, key := range m.apiKeys {
+		if key.Prefix == prefix && key.RevokedAt == nil && (key.ExpiresAt == nil || key.ExpiresAt.After(now)) {
+			return &key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("api key %w", ErrNotFound)
+}
+
+// TouchAPIKey records that an API key has just been used.
+func (m *MemoryStore) TouchAPIKey(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if key, ok := m.apiKeys[id]; ok {
+		now := time.Now()
+		key.LastUsedAt = &now
+		m.apiKeys[id] = key
+	}
+
+	return nil
+}
+
+// RevokeAPIKey revokes an API key of a user.
+func (m *MemoryStore) RevokeAPIKey(userID, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.apiKeys[id]
+	if !ok || key.UserID != userID || key.RevokedAt != nil {
+		return fmt.Errorf("api key %w", ErrNotFound)
+	}
+
+	now := time.Now()
+	key.RevokedAt = &now
+	m.apiKeys[id] = key
+
+	return nil
+}
+
+// IncrementQuota counts a request of the client for the day (YYYY-MM-DD) and returns the new count.
+func (m *MemoryStore) IncrementQuota(clientKey string, day string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.quotas[day] == nil {
+		m.quotas[day] = make(map[string]int)
+	}
+	m.quotas[day][clientKey]++
+
+	return m.quotas[day][clientKey], nil
+}
+
+// DeleteQuotasBefore removes the quota counters of days before the given day (YYYY-MM-DD).
+func (m *MemoryStore) DeleteQuotasBefore(day string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for d := range m.quotas {
+		if d < day {
+			delete(m.quotas, d)
+		}
+	}
+
+	return nil
+}
+
+// InsertAuditEvent stores an audit event.
+func (m *MemoryStore) InsertAuditEvent(event models.AuditEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	event.ID = int64(len(m.audit) + 1)
+	event.Details = copyDetails(event.Details)
+	m.audit = append(m.audit, event)
+
+	return nil
+}
+
+// ListAuditEvents retrieves audit events matching the filter, newest first.
+func (m *MemoryStore) ListAuditEvents(filter models.AuditFilter) ([]models.AuditEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events := []models.AuditEvent{}
+	for _, event := range m.audit {
+		if filter.Username != "" && event.Username != filter.Username ||
+			filter.Type != "" && event.Type != filter.Type ||
+			filter.Resource != "" && event.Resource != filter.Resource ||
+			filter.RequestID != "" && event.RequestID != filter.RequestID ||
+			filter.From != nil && event.OccurredAt.Before(*filter.From) ||
+			filter.To != nil && !event.OccurredAt.Before(*filter.To) {
+			continue
+		}
+		event.Details = copyDetails(event.Details)
+		events = append(events, event)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].OccurredAt.Equal(events[j].OccurredAt) {
+			return events[i].OccurredAt.After(events[j].OccurredAt)
+		}
+		return events[i].ID > events[j].ID
+	})
+
+	if filter.Offset >= len(events) {
+		return []models.AuditEvent{}, nil
+	}
+	events = events[filter.Offset:]
+	if filter.Limit >= 0 && filter.Limit < len(events) {
+		events = events[:filter.Limit]
+	}
+
+	return events, nil
+}
+
+func copyDetails(details map[string]string) map[string]string {
+	if details == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(details))
+	for k, v := range details {
+		copied[k] = v
+	}
+	return copied
+}
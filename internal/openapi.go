@@ -0,0 +1,196 @@
+package internal
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+)
+
+var (
+	//go:embed docs/openapi.json
+	openAPISpec []byte
+
+	//go:embed docs/swagger.html
+	swaggerUI []byte
+
+	// openAPIOnce parses the embedded document into openAPIDoc or openAPIErr
+	// the first time it is needed.
+	openAPIOnce sync.Once
+	openAPIDoc  *openapi3.T
+	openAPIErr  error
+
+	// routeVariablePattern matches mux path variables with a regular expression, e.g. {id:[0-9]+}.
+	routeVariablePattern = regexp.MustCompile(`\{([^}:]+):[^}]+\}`)
+)
+
+// loadOpenAPI returns the parsed and validated OpenAPI document.
+func loadOpenAPI() (*openapi3.T, error) {
+	openAPIOnce.Do(func() {
+		loader := openapi3.NewLoader()
+
+		doc, err := loader.LoadFromData(openAPISpec)
+		if err != nil {
+			openAPIErr = fmt.Errorf("could not load the OpenAPI document: %w", err)
+			return
+		}
+
+		if err = doc.Validate(context.Background()); err != nil {
+			openAPIErr = fmt.Errorf("invalid OpenAPI document: %w", err)
+			return
+		}
+
+		openAPIDoc = doc
+	})
+
+	return openAPIDoc, openAPIErr
+}
+
+// OpenAPIHandler function for serving the OpenAPI document
+func (h Handlers) OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPISpec)
+}
+
+// SwaggerUIHandler function for serving the Swagger UI
+func (h Handlers) SwaggerUIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(swaggerUI)
+}
+
+// ValidateRequests returns a middleware that rejects requests whose
+// parameters or body do not match the OpenAPI document with 400.
+// Authentication is left to RequireTokenAuthentication.
+func ValidateRequests() (mux.MiddlewareFunc, error) {
+	doc, err := loadOpenAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("could not build the OpenAPI router: %w", err)
+	}
+
+	options := &openapi3filter.Options{
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				// Routes missing in the document are reported by CheckOpenAPIRoutes
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			})
+			if err != nil {
+				http.Error(w, validationMessage(err), http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// CheckOpenAPIRoutes compares the routes of a handler built by MakeHTTPHandler
+// with the OpenAPI document and returns an error listing every route that is
+// missing on either side.
+func CheckOpenAPIRoutes(handler http.Handler) error {
+	router, ok := handler.(*mux.Router)
+	if !ok {
+		return fmt.Errorf("unexpected handler type %T", handler)
+	}
+
+	doc, err := loadOpenAPI()
+	if err != nil {
+		return err
+	}
+
+	documented := map[string]bool{}
+	for path, item := range doc.Paths {
+		for method := range item.Operations() {
+			documented[method+" "+path] = false
+		}
+	}
+
+	var problems []string
+
+	err = router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			// Subrouters without a path of their own
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Path prefixes of subrouters
+			return nil
+		}
+
+		path = routeVariablePattern.ReplaceAllString(path, "{$1}")
+		for _, method := range methods {
+			key := method + " " + path
+			if _, ok := documented[key]; !ok {
+				problems = append(problems, "undocumented route "+key)
+				continue
+			}
+			documented[key] = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for key, found := range documented {
+		if !found {
+			problems = append(problems, "documented route without handler "+key)
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("routes and OpenAPI document differ: %s", strings.Join(problems, ", "))
+	}
+
+	return nil
+}
+
+// validationMessage shortens kin-openapi errors to what is useful for clients.
+func validationMessage(err error) string {
+	switch e := err.(type) {
+	case *openapi3filter.RequestError:
+		if e.Parameter != nil {
+			return fmt.Sprintf("invalid %s parameter %q: %v", e.Parameter.In, e.Parameter.Name, e.Err)
+		}
+		if e.RequestBody != nil {
+			return fmt.Sprintf("invalid request body: %v", e.Err)
+		}
+		return e.Error()
+	case *routers.RouteError:
+		return e.Reason
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,218 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+
+	"awesomeProject/models"
+)
+
+func TestOpenAPIDocumentIsValid(t *testing.T) {
+	if _, err := loadOpenAPI(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenAPIRoutesMatchHandlers(t *testing.T) {
+	api := newTestAPI(t)
+
+	if err := CheckOpenAPIRoutes(api.handler); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestOpenAPIResponses sends requests to every operation and validates the
+// status, headers and body of the responses against the OpenAPI document.
+func TestOpenAPIResponses(t *testing.T) {
+	api := newTestAPI(t)
+
+	doc, err := loadOpenAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// kin-openapi only decodes JSON, forms and files, the Swagger UI is checked as text
+	openapi3filter.RegisterBodyDecoder("text/html", openapi3filter.FileBodyDecoder)
+	defer openapi3filter.UnregisterBodyDecoder("text/html")
+
+	// Users, sessions and keys the requests below change, so they do not affect each other
+	target, err := api.server.CreateUser(models.CreateUserRequest{Username: "target", Password: testPassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed, err := api.server.CreateUser(models.CreateUserRequest{Username: "removed", Password: testPassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.server.CreateUser(models.CreateUserRequest{Username: "changer", Password: testPassword}); err != nil {
+		t.Fatal(err)
+	}
+	changer := api.login("changer").AccessToken
+	leaving := api.login(string(models.RoleViewer)).AccessToken
+	refresh := api.login(string(models.RoleViewer)).RefreshToken
+	otherSession := api.sessionID(api.login(string(models.RoleAnalyst)).AccessToken)
+
+	key, err := api.server.CreateAPIKey(string(models.RoleAnalyst), models.CreateAPIKeyRequest{Name: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		role   models.Role
+		token  string
+		body   interface{}
+		status int
+	}{
+		{"POST", "/login", "", "", models.LoginRequest{Username: "viewer", Password: testPassword}, http.StatusOK},
+		{"POST", "/login", "", "", models.LoginRequest{Username: "viewer", Password: "wrong password"}, http.StatusUnauthorized},
+		{"POST", "/login", "", "", map[string]int{"username": 1}, http.StatusBadRequest},
+		{"POST", "/token/refresh", "", "", models.RefreshRequest{RefreshToken: refresh}, http.StatusOK},
+		{"POST", "/token/refresh", "", "", models.RefreshRequest{RefreshToken: "invalid"}, http.StatusUnauthorized},
+		{"GET", "/.well-known/jwks.json", "", "", nil, http.StatusOK},
+		{"GET", "/openapi.json", "", "", nil, http.StatusOK},
+		{"GET", "/docs", "", "", nil, http.StatusOK},
+		{"GET", "/healthz", "", "", nil, http.StatusOK},
+		{"GET", "/readyz", "", "", nil, http.StatusOK},
+
+		{"GET", "/secured/etfs", "", "", nil, http.StatusUnauthorized},
+		{"GET", "/secured/etfs", models.RoleViewer, "", nil, http.StatusOK},
+		{"GET", "/secured/etf/SPY", models.RoleViewer, "", nil, http.StatusOK},
+		{"GET", "/secured/etf/NONE", models.RoleViewer, "", nil, http.StatusNotFound},
+		{"GET", "/secured/etf/SPY/holdings?page=1&page_size=2", models.RoleViewer, "", nil, http.StatusOK},
+		{"GET", "/secured/etf/SPY/holdings?page=0", models.RoleViewer, "", nil, http.StatusBadRequest},
+		{"GET", "/secured/etf/QQQ/holdings", models.RoleViewer, "", nil, http.StatusNotFound},
+		{"GET", "/secured/etf/SPY/breakdowns", models.RoleViewer, "", nil, http.StatusOK},
+		{"GET", "/secured/etf/SPY/breakdowns?level=industry", models.RoleViewer, "", nil, http.StatusOK},
+		{"GET", "/secured/etf/SPY/breakdowns?level=unknown", models.RoleViewer, "", nil, http.StatusBadRequest},
+		{"GET", "/secured/etf/SPY/distributions", models.RoleViewer, "", nil, http.StatusOK},
+		{"GET", "/secured/etf/SPY/distributions?from=2024-01-01&to=2024-12-31", models.RoleViewer, "", nil, http.StatusOK},
+		{"GET", "/secured/etf/SPY/distributions?from=2024-12-31&to=2024-01-01", models.RoleViewer, "", nil, http.StatusBadRequest},
+
+		{"GET", "/secured/export", models.RoleViewer, "", nil, http.StatusForbidden},
+		{"GET", "/secured/export", models.RoleAnalyst, "", nil, http.StatusOK},
+		{"GET", "/secured/overlap?tickers=SPY,QQQ", models.RoleAnalyst, "", nil, http.StatusOK},
+		{"GET", "/secured/overlap?tickers=SPY", models.RoleAnalyst, "", nil, http.StatusBadRequest},
+		{"GET", "/secured/overlap?tickers=SPY,NONE", models.RoleAnalyst, "", nil, http.StatusNotFound},
+		{"POST", "/secured/exposure", models.RoleAnalyst, "", models.ExposureRequest{Positions: []models.Position{{Ticker: "SPY", Weight: 60}, {Ticker: "QQQ", Weight: 40}}}, http.StatusOK},
+		{"POST", "/secured/exposure", models.RoleAnalyst, "", models.ExposureRequest{}, http.StatusBadRequest},
+		{"GET", "/secured/etf/SPY/tracking", models.RoleAnalyst, "", nil, http.StatusOK},
+		{"GET", "/secured/etf/QQQ/tracking", models.RoleAnalyst, "", nil, http.StatusNotFound},
+		{"GET", "/secured/etf/SPY/tracking", models.RoleViewer, "", nil, http.StatusForbidden},
+
+		{"GET", "/graphql?query=%7Betf(ticker:%22SPY%22)%7Bticker%7D%7D", models.RoleViewer, "", nil, http.StatusOK},
+		{"POST", "/graphql", models.RoleAnalyst, "", GraphQLRequest{Query: "{ etfs { ticker } }"}, http.StatusOK},
+
+		{"GET", "/secured/me/sessions", models.RoleAnalyst, "", nil, http.StatusOK},
+		{"DELETE", "/secured/me/sessions/" + otherSession, models.RoleAnalyst, "", nil, http.StatusNoContent},
+		{"DELETE", "/secured/me/sessions/unknown", models.RoleAnalyst, "", nil, http.StatusNotFound},
+		{"GET", "/secured/me/apikeys", models.RoleAnalyst, "", nil, http.StatusOK},
+		{"POST", "/secured/me/apikeys", models.RoleAnalyst, "", models.CreateAPIKeyRequest{Name: "reports", Scope: models.RoleViewer}, http.StatusCreated},
+		{"DELETE", fmt.Sprintf("/secured/me/apikeys/%d", key.ID), models.RoleAnalyst, "", nil, http.StatusNoContent},
+		{"DELETE", "/secured/me/apikeys/999", models.RoleAnalyst, "", nil, http.StatusNotFound},
+		{"PUT", "/secured/me/password", "", changer, models.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: "another long passphrase"}, http.StatusNoContent},
+		{"DELETE", "/secured/me/sessions", "", changer, nil, http.StatusNoContent},
+
+		{"GET", "/admin/users", models.RoleAnalyst, "", nil, http.StatusForbidden},
+		{"GET", "/admin/users", models.RoleAdmin, "", nil, http.StatusOK},
+		{"POST", "/admin/users", models.RoleAdmin, "", models.CreateUserRequest{Username: "new", Password: testPassword}, http.StatusCreated},
+		{"POST", "/admin/users", models.RoleAdmin, "", models.CreateUserRequest{Username: "new", Password: testPassword}, http.StatusConflict},
+		{"POST", fmt.Sprintf("/admin/users/%d/disable", target.ID), models.RoleAdmin, "", nil, http.StatusNoContent},
+		{"POST", fmt.Sprintf("/admin/users/%d/enable", target.ID), models.RoleAdmin, "", nil, http.StatusNoContent},
+		{"PUT", fmt.Sprintf("/admin/users/%d/role", target.ID), models.RoleAdmin, "", models.UpdateRoleRequest{Role: models.RoleAnalyst}, http.StatusNoContent},
+		{"PUT", fmt.Sprintf("/admin/users/%d/role", target.ID), models.RoleAdmin, "", models.UpdateRoleRequest{Role: "owner"}, http.StatusBadRequest},
+		{"PUT", fmt.Sprintf("/admin/users/%d/password", target.ID), models.RoleAdmin, "", models.ResetPasswordRequest{Password: "a new long passphrase"}, http.StatusNoContent},
+		{"DELETE", fmt.Sprintf("/admin/users/%d", removed.ID), models.RoleAdmin, "", nil, http.StatusNoContent},
+		{"DELETE", "/admin/users/999", models.RoleAdmin, "", nil, http.StatusNotFound},
+		{"GET", "/admin/audit?limit=10", models.RoleAdmin, "", nil, http.StatusOK},
+
+		{"POST", "/logout", "", leaving, nil, http.StatusNoContent},
+	}
+
+	for _, test := range tests {
+		t.Run(test.method+" "+test.path, func(t *testing.T) {
+			token := test.token
+			if token == "" {
+				token = api.tokens[test.role]
+			}
+
+			req := newTestRequest(t, test.method, test.path, test.body)
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+
+			rec := httptest.NewRecorder()
+			api.handler.ServeHTTP(rec, req)
+
+			if rec.Code != test.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, test.status, rec.Body)
+			}
+
+			validateOpenAPIResponse(t, router, req, rec)
+		})
+	}
+}
+
+// validateOpenAPIResponse fails the test when the response is not described
+// by the operation the request was routed to.
+func validateOpenAPIResponse(t *testing.T, router routers.Router, req *http.Request, rec *httptest.ResponseRecorder) {
+	t.Helper()
+
+	route, pathParams, err := router.FindRoute(req)
+	if err != nil {
+		t.Fatalf("find route: %v", err)
+	}
+
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status:  rec.Code,
+		Header:  rec.Header(),
+		Body:    io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+		Options: &openapi3filter.Options{IncludeResponseStatus: true},
+	})
+	if err != nil {
+		t.Errorf("response does not match the OpenAPI document: %v", err)
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	api := newTestAPI(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+	}{
+		{"parameter of the wrong type", "GET", "/secured/etf/SPY/holdings?page=first", nil},
+		{"unknown enum value", "GET", "/secured/etf/SPY/breakdowns?level=country", nil},
+		{"body missing required fields", "POST", "/secured/exposure", map[string]string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := api.do(test.method, test.path, models.RoleAnalyst, test.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body)
+			}
+		})
+	}
+}
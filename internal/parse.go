@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseNumber parses numbers as shown on the fund pages, e.g. "1,234,567.89",
+// "$12.34" or "7.12%". It reports false for empty values and placeholders like "--".
+func parseNumber(value string) (float64, bool) {
+	cleaned := strings.NewReplacer(",", "", "$", "", "%", "", " ", "").Replace(strings.TrimSpace(value))
+	if cleaned == "" {
+		return 0, false
+	}
+
+	n, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// parseWeight parses a weight in percent, e.g. "7.12%". Missing weights count as zero.
+func parseWeight(value string) float64 {
+	n, _ := parseNumber(value)
+	return n
+}
+
+// amountPattern matches a number with an optional magnitude suffix, e.g.
+// "$513,541.07 M", "1.2bn" or "$12.34 Million". Currency symbols and codes
+// before it are skipped.
+var amountPattern = regexp.MustCompile(`(?i)^[^0-9-]*(-?[0-9,]*\.?[0-9]+)(?:\s*(k|m|mn|mm|b|bn|t|tn|thousand|million|billion|trillion)\b)?`)
+
+// amountMultipliers maps the magnitude suffixes of amountPattern.
+var amountMultipliers = map[string]float64{
+	"k":  1e3,
+	"m":  1e6,
+	"mn": 1e6,
+	"mm": 1e6,
+	"b":  1e9,
+	"bn": 1e9,
+	"t":  1e12,
+	"tn": 1e12,
+
+	"thousand": 1e3,
+	"million":  1e6,
+	"billion":  1e9,
+	"trillion": 1e12,
+}
+
+// parseAmount parses amounts as shown on the fund pages, e.g. "$512.34",
+// "USD 513,541.07 M" or "0.0945%", and applies the magnitude suffix.
+// It reports false when the value does not start with a number.
+func parseAmount(value string) (float64, bool) {
+	match := amountPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, false
+	}
+
+	n, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	if multiplier, ok := amountMultipliers[strings.ToLower(match[2])]; ok {
+		n *= multiplier
+	}
+
+	return n, true
+}
+
+// dateLayouts are the date formats used on the fund pages.
+var dateLayouts = []string{
+	"Jan 2 2006",
+	"Jan 2, 2006",
+	"January 2 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+	"2-Jan-2006",
+	"01/02/2006",
+	"2006-01-02",
+}
+
+// parseDate parses a date as shown on the fund pages and formats it as YYYY-MM-DD.
+// It reports false for values in an unknown format.
+func parseDate(value string) (string, bool) {
+	value = strings.Join(strings.Fields(value), " ")
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02"), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		ok    bool
+	}{
+		{"1,234,567.89", 1234567.89, true},
+		{"$12.34", 12.34, true},
+		{"7.12%", 7.12, true},
+		{" -0.5\u00a0% ", -0.5, true},
+		{"503", 503, true},
+		{"", 0, false},
+		{"--", 0, false},
+		{"—", 0, false},
+		{"N/A", 0, false},
+		{"12 M", 0, false},
+		{"1.2.3", 0, false},
+	}
+
+	for _, test := range tests {
+		got, ok := parseNumber(test.value)
+		if ok != test.ok || got != test.want {
+			t.Errorf("parseNumber(%q) = %v, %v, want %v, %v", test.value, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		ok    bool
+	}{
+		{"$512.34", 512.34, true},
+		{"0.0945%", 0.0945, true},
+		{"USD 513,541.07 M", 513541.07e6, true},
+		{"$350M", 350e6, true},
+		{"1.2 billion", 1.2e9, true},
+		{"1.2bn", 1.2e9, true},
+		{"$12.34 Million", 12.34e6, true},
+		{"€ 3.5 Tn", 3.5e12, true},
+		{"750k", 750e3, true},
+		{"-1.5%", -1.5, true},
+		// "Main" is not a magnitude suffix
+		{"12 Main Street", 12, true},
+		{"", 0, false},
+		{"—", 0, false},
+		{"--", 0, false},
+		{"N/A", 0, false},
+		{"$", 0, false},
+	}
+
+	for _, test := range tests {
+		got, ok := parseAmount(test.value)
+		if ok != test.ok || math.Abs(got-test.want) > 1e-6*math.Abs(test.want) {
+			t.Errorf("parseAmount(%q) = %v, %v, want %v, %v", test.value, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"Jan 22 1993", "1993-01-22", true},
+		{"Jan 22, 1993", "1993-01-22", true},
+		{"January 22, 1993", "1993-01-22", true},
+		{"22 Jan 1993", "1993-01-22", true},
+		{"22-Jan-1993", "1993-01-22", true},
+		{"01/22/1993", "1993-01-22", true},
+		{"1993-01-22", "1993-01-22", true},
+		{"  Jan\n  22,   1993 ", "1993-01-22", true},
+		{"22/01/1993", "", false},
+		{"Feb 30 1993", "", false},
+		{"—", "", false},
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		got, ok := parseDate(test.value)
+		if ok != test.ok || got != test.want {
+			t.Errorf("parseDate(%q) = %q, %v, want %q, %v", test.value, got, ok, test.want, test.ok)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// legacyHashLength is the length of the hex encoded unsalted SHA-256 hashes
+// the users table was originally seeded with.
+const legacyHashLength = sha256.Size * 2
+
+// dummyPasswordHash is compared against when the user does not exist so that
+// unknown usernames take as long to reject as wrong passwords.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+// hashPassword returns a salted bcrypt hash of the password.
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// checkPassword verifies the password against the stored hash.
+// The second return value reports whether the stored hash uses a legacy
+// format and should be replaced with a fresh hash.
+func checkPassword(hash, password string) (ok bool, needsRehash bool) {
+	if isLegacyHash(hash) {
+		legacy := legacyHash(password)
+		return subtle.ConstantTimeCompare([]byte(hash), []byte(legacy)) == 1, true
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return false, false
+	}
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	return true, err != nil || cost < bcrypt.DefaultCost
+}
+
+// isLegacyHash reports whether the hash is an unsalted SHA-256 hex digest.
+func isLegacyHash(hash string) bool {
+	if len(hash) != legacyHashLength {
+		return false
+	}
+
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
+
+func legacyHash(input string) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(input))
+	hashBytes := hasher.Sum(nil)
+
+	return hex.EncodeToString(hashBytes)
+}
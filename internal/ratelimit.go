@@ -0,0 +1,261 @@
+package internal
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"awesomeProject/models"
+)
+
+const (
+	// bucketSweepInterval is how often idle buckets are dropped from memory.
+	bucketSweepInterval = time.Minute
+
+	// quotaRetentionDays is how many days of quota counters are kept.
+	quotaRetentionDays = 7
+
+	quotaDayLayout = "2006-01-02"
+)
+
+// RateLimit configures a token bucket and an optional daily quota.
+type RateLimit struct {
+	// Rate is the number of requests per second the bucket refills with.
+	Rate float64
+	// Burst is the size of the bucket.
+	Burst int
+	// DailyQuota is the number of requests allowed per UTC day, 0 means unlimited.
+	DailyQuota int
+}
+
+// RateLimitConfig holds the limits per role and for anonymous clients.
+type RateLimitConfig struct {
+	Roles     map[models.Role]RateLimit
+	Anonymous RateLimit
+}
+
+// DefaultRateLimitConfig returns the limits used when nothing else is configured.
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Roles: map[models.Role]RateLimit{
+			models.RoleViewer:  {Rate: 5, Burst: 20, DailyQuota: 20000},
+			models.RoleAnalyst: {Rate: 10, Burst: 40, DailyQuota: 50000},
+			models.RoleAdmin:   {Rate: 20, Burst: 80},
+		},
+		Anonymous: RateLimit{Rate: 1, Burst: 5},
+	}
+}
+
+// QuotaCounter persists daily request counters so quotas hold across replicas.
+type QuotaCounter interface {
+	IncrementQuota(clientKey string, day string) (int, error)
+	DeleteQuotasBefore(day string) error
+}
+
+// RateLimiter limits requests per client with in-memory token buckets and
+// persisted daily quotas.
+type RateLimiter struct {
+	config RateLimitConfig
+	quotas QuotaCounter
+	logger *logrus.Logger
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	lastDay   string
+}
+
+func NewRateLimiter(config RateLimitConfig, quotas QuotaCounter, logger *logrus.Logger) *RateLimiter {
+	return &RateLimiter{
+		config:  config,
+		quotas:  quotas,
+		logger:  logger,
+		buckets: map[string]*bucket{},
+	}
+}
+
+// Limit middleware function that limits authenticated requests per API key or username.
+// It must run after RequireTokenAuthentication.
+func (l *RateLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := principalFromContext(r.Context())
+		if p == nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		key, limit := l.principalLimit(p)
+		if l.allow(w, key, limit) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// principalLimit returns the bucket key and the limit of an authenticated client.
+func (l *RateLimiter) principalLimit(p *principal) (string, RateLimit) {
+	limit, ok := l.config.Roles[p.Role]
+	if !ok {
+		limit = l.config.Roles[models.RoleViewer]
+	}
+
+	key := "user:" + p.Username
+	if p.APIKeyID != 0 {
+		key = "apikey:" + strconv.Itoa(p.APIKeyID)
+	}
+
+	return key, limit
+}
+
+// LimitAnonymous middleware function that limits unauthenticated requests per client IP.
+func (l *RateLimiter) LimitAnonymous(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.allow(w, "ip:"+clientIP(r), l.config.Anonymous) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// allow takes a token from the client's bucket and counts the request against
+// its daily quota. It writes the RateLimit-* headers and, when the request is
+// rejected, a 429 response.
+func (l *RateLimiter) allow(w http.ResponseWriter, key string, limit RateLimit) bool {
+	if limit.Rate <= 0 || limit.Burst <= 0 {
+		return true
+	}
+
+	ok, remaining, wait, reset := l.check(key, limit)
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(reset)))
+
+	if !ok {
+		tooManyRequests(w, wait)
+		return false
+	}
+
+	return true
+}
+
+// check takes a token from the client's bucket and counts the request against
+// its daily quota. It returns whether the request is allowed, the tokens left,
+// how long a rejected client has to wait and when the bucket is full again.
+func (l *RateLimiter) check(key string, limit RateLimit) (bool, int, time.Duration, time.Duration) {
+	if limit.Rate <= 0 || limit.Burst <= 0 {
+		return true, 0, 0, 0
+	}
+
+	now := time.Now()
+	ok, remaining, wait, reset := l.take(key, limit, now)
+	if !ok || limit.DailyQuota <= 0 {
+		return ok, remaining, wait, reset
+	}
+
+	day := now.UTC().Format(quotaDayLayout)
+	l.cleanupQuotas(now)
+
+	count, err := l.quotas.IncrementQuota(key, day)
+	if err != nil {
+		// Do not lock everyone out when the database has problems
+		l.logger.Errorf("Could not count request against the daily quota of %s: %v", key, err)
+		return true, remaining, 0, reset
+	}
+
+	if count > limit.DailyQuota {
+		l.logger.Warnf("Daily quota of %d requests exceeded by %s", limit.DailyQuota, key)
+		tomorrow := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+		return false, remaining, tomorrow.Sub(now), reset
+	}
+
+	return true, remaining, 0, reset
+}
+
+func (l *RateLimiter) take(key string, limit RateLimit, now time.Time) (bool, int, time.Duration, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > bucketSweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+
+	return b.take(now)
+}
+
+// sweep drops buckets that have refilled completely, they are recreated full on demand.
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// cleanupQuotas removes old quota counters once per day.
+func (l *RateLimiter) cleanupQuotas(now time.Time) {
+	day := now.UTC().Format(quotaDayLayout)
+
+	l.mu.Lock()
+	if l.lastDay == day {
+		l.mu.Unlock()
+		return
+	}
+	l.lastDay = day
+	l.mu.Unlock()
+
+	before := now.UTC().AddDate(0, 0, -quotaRetentionDays).Format(quotaDayLayout)
+	if err := l.quotas.DeleteQuotasBefore(before); err != nil {
+		l.logger.Errorf("Could not delete old quota counters: %v", err)
+	}
+}
+
+type bucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+		b.last = now
+	}
+}
+
+func (b *bucket) take(now time.Time) (bool, int, time.Duration, time.Duration) {
+	b.refill(now)
+
+	ok := b.tokens >= 1
+	if ok {
+		b.tokens--
+	}
+
+	wait := time.Duration(0)
+	if b.tokens < 1 {
+		wait = time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second))
+	}
+	reset := time.Duration((float64(b.limit.Burst) - b.tokens) / b.limit.Rate * float64(time.Second))
+
+	return ok, int(b.tokens), wait, reset
+}
+
+func tooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+	w.WriteHeader(http.StatusTooManyRequests)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -1,27 +1,76 @@
 package internal
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
 
 	"awesomeProject/models"
 )
 
+const (
+	// minPasswordLength is the shortest password accepted for new or changed passwords.
+	minPasswordLength = 8
+
+	// maxPasswordLength is the longest password in bytes bcrypt can hash.
+	maxPasswordLength = 72
+
+	// refreshTokenTTL is how long a session stays valid without being refreshed.
+	refreshTokenTTL = 30 * 24 * time.Hour
+
+	// seededAdminUsername and seededAdminPassword are the credentials 1_init
+	// used to seed into every database.
+	seededAdminUsername = "admin"
+	seededAdminPassword = "admin"
+)
+
+var (
+	// ErrInvalidCredentials is returned when the username or password does not match.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrUserDisabled is returned when a disabled user tries to log in.
+	ErrUserDisabled = errors.New("user is disabled")
+	// ErrInvalidInput is returned when a request does not pass validation.
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+const (
+	// defaultAuditLimit and maxAuditLimit bound the number of audit events returned at once.
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+
+	// defaultHoldingsPageSize and maxHoldingsPageSize bound the number of holdings returned at once.
+	defaultHoldingsPageSize = 100
+	maxHoldingsPageSize     = 1000
+)
+
 type Server struct {
-	logger *logrus.Logger
-	store  *Database
+	logger  *logrus.Logger
+	store   Store
+	auditor *Auditor
+	updates *UpdateBroker
 }
 
-func NewServer(logger *logrus.Logger, store *Database) *Server {
+func NewServer(logger *logrus.Logger, store Store, updates *UpdateBroker) *Server {
 	return &Server{
-		logger: logger,
-		store:  store,
+		logger:  logger,
+		store:   store,
+		auditor: NewAuditor(store, logger),
+		updates: updates,
 	}
 }
 
+// Close writes the queued audit events, the server must not be used afterwards.
+func (s Server) Close() {
+	s.auditor.Close()
+}
+
 func (s Server) GetAllTickers() ([]string, error) {
 	return s.store.GetAllIDs()
 }
@@ -41,14 +90,660 @@ func (s Server) GetETF(ticker string) (*models.ETFData, error) {
 	return &data, nil
 }
 
-func (s Server) UserExists(username, password string) (bool, error) {
-	return s.store.UserExists(username, toHash(password))
+// GetBreakdowns returns the breakdowns of an ETF, only the one of level
+// unless level is empty. A level the fund page does not show is not found.
+func (s Server) GetBreakdowns(ticker string, level models.BreakdownLevel) ([]models.Breakdown, error) {
+	if level != "" && !level.Valid() {
+		return nil, fmt.Errorf("%w: unknown breakdown level %q", ErrInvalidInput, level)
+	}
+
+	etf, err := s.GetETF(ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	if level == "" {
+		if etf.Breakdowns == nil {
+			return []models.Breakdown{}, nil
+		}
+		return etf.Breakdowns, nil
+	}
+
+	for _, breakdown := range etf.Breakdowns {
+		if breakdown.Level == level {
+			return []models.Breakdown{breakdown}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s breakdown %w", level, ErrNotFound)
 }
 
-func toHash(input string) string {
-	hasher := sha256.New()
-	hasher.Write([]byte(input))
-	hashBytes := hasher.Sum(nil)
+// GetDistributions returns the distributions of an ETF with an ex-date
+// between from and to, formatted as YYYY-MM-DD and unbounded when empty.
+// The trailing 12 month figures do not depend on the range.
+func (s Server) GetDistributions(ticker, from, to string) (*models.DistributionHistory, error) {
+	if from != "" && to != "" && from > to {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidInput)
+	}
+
+	etf, err := s.GetETF(ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &models.DistributionHistory{Ticker: etf.Name, From: from, To: to}
+
+	history.Distributions, err = s.store.ListDistributions(etf.Name, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ttm, err := s.store.ListDistributions(etf.Name, now.AddDate(-1, 0, 0).Format("2006-01-02"), now.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, distribution := range ttm {
+		history.TTMAmount += distribution.Amount
+	}
+
+	if etf.Characteristics != nil && etf.Characteristics.NAV != nil && *etf.Characteristics.NAV > 0 {
+		yield := history.TTMAmount / *etf.Characteristics.NAV * 100
+		history.TTMYield = &yield
+	}
+
+	return history, nil
+}
+
+// GetHoldings returns a page of the full holdings of an ETF, pages start at 1.
+func (s Server) GetHoldings(ticker string, page, pageSize int) (*models.HoldingsPage, error) {
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultHoldingsPageSize
+	}
+
+	if page < 1 || pageSize < 1 || pageSize > maxHoldingsPageSize {
+		return nil, fmt.Errorf("%w: page must be positive and page_size between 1 and %d", ErrInvalidInput, maxHoldingsPageSize)
+	}
+
+	// The offset of the page would overflow
+	if page > math.MaxInt/pageSize {
+		return nil, fmt.Errorf("%w: page must be at most %d for a page_size of %d", ErrInvalidInput, math.MaxInt/pageSize, pageSize)
+	}
+
+	holdings, err := s.store.ListHoldings(ticker, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	holdings.Page = page
+	holdings.PageSize = pageSize
+
+	return holdings, nil
+}
+
+// ExportETFs returns the data of every stored ETF.
+func (s Server) ExportETFs() ([]models.ETFData, error) {
+	etfs, err := s.store.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.ETFData, len(etfs))
+	for i := range etfs {
+		if err := json.Unmarshal(etfs[i].Data, &result[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// WatchUpdates returns a channel receiving the ETFs of the tickers, or of all
+// ETFs, the updater stores from now on. The returned function has to be called
+// when the caller stops watching.
+func (s Server) WatchUpdates(tickers []string) (<-chan models.ETFUpdate, func()) {
+	return s.updates.Subscribe(tickers)
+}
+
+// Authenticate checks the username and password and returns the matching user.
+// Legacy password hashes are upgraded after a successful login.
+func (s Server) Authenticate(username, password string) (*models.User, error) {
+	user, err := s.store.GetUserByUsername(username)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			// Spend the same time as for an existing user
+			_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	ok, needsRehash := checkPassword(user.Password, password)
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	if needsRehash {
+		// The login already succeeded, a failed upgrade is retried on the next one
+		hash, err := hashPassword(password)
+		if err == nil {
+			err = s.store.UpdateUserPassword(user.ID, hash)
+		}
+		if err != nil {
+			s.logger.Errorf("Could not upgrade password hash for user %s: %v", username, err)
+		} else {
+			user.Password = hash
+		}
+	}
+
+	if err = s.store.UpdateLastLogin(user.ID); err != nil {
+		s.logger.Errorf("Could not update last login for user %s: %v", username, err)
+	}
+
+	return user, nil
+}
+
+// CreateUser validates the request and creates a new user.
+func (s Server) CreateUser(req models.CreateUserRequest) (*models.User, error) {
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Username == "" {
+		return nil, fmt.Errorf("%w: username is required", ErrInvalidInput)
+	}
+
+	if req.Email != "" && !strings.Contains(req.Email, "@") {
+		return nil, fmt.Errorf("%w: email is not valid", ErrInvalidInput)
+	}
+
+	if req.Role == "" {
+		req.Role = models.RoleViewer
+	}
+
+	if !req.Role.Valid() {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidInput, req.Role)
+	}
+
+	if err := validatePassword(req.Password); err != nil {
+		return nil, err
+	}
+
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.store.CreateUser(models.User{
+		Username: req.Username,
+		Password: hash,
+		Email:    req.Email,
+		Role:     req.Role,
+	})
+}
+
+// BootstrapAdmin makes sure the server can be administered without a known
+// credential. When there is no admin yet, it creates one with the username and
+// password. When the admin seeded by older migrations still accepts the
+// default password, that password is replaced and its sessions are revoked.
+// An empty password is replaced with a generated one, which is logged once.
+// Replicas may bootstrap concurrently: only the one that creates the admin or
+// replaces the password logs it, the others leave the result alone.
+func (s Server) BootstrapAdmin(username, password string) error {
+	users, err := s.store.ListUsers()
+	if err != nil {
+		return err
+	}
+
+	generated := password == ""
+	if generated {
+		if password, err = randomSecret(18); err != nil {
+			return err
+		}
+	}
+
+	for _, user := range users {
+		if user.Username != seededAdminUsername {
+			continue
+		}
+		if ok, _ := checkPassword(user.Password, seededAdminPassword); !ok {
+			continue
+		}
+
+		return s.replaceSeededPassword(user, password, generated)
+	}
+
+	for _, user := range users {
+		if user.Role == models.RoleAdmin {
+			return nil
+		}
+	}
+
+	_, err = s.CreateUser(models.CreateUserRequest{
+		Username: username,
+		Password: password,
+		Role:     models.RoleAdmin,
+	})
+	if errors.Is(err, ErrAlreadyExists) {
+		return s.checkExistingAdmin(username)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Infof("Created the admin user %s", username)
+	if generated {
+		s.logger.Warnf("The password of the %s user is %s, it is not shown again", username, password)
+	}
+
+	return nil
+}
+
+// replaceSeededPassword replaces the default password of the seeded admin,
+// unless another replica replaced it in the meantime.
+func (s Server) replaceSeededPassword(user models.User, password string, generated bool) error {
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	replaced, err := s.store.ReplaceUserPassword(user.ID, user.Password, hash)
+	if err != nil {
+		return err
+	}
+
+	if !replaced {
+		s.logger.Infof("The default password of the %s user was already replaced", user.Username)
+		return nil
+	}
+
+	if err := s.store.RevokeUserSessions(user.ID, ""); err != nil {
+		return err
+	}
+
+	s.logger.Warnf("The %s user still had the default password, it was replaced", user.Username)
+	if generated {
+		s.logger.Warnf("The new password of the %s user is %s, it is not shown again", user.Username, password)
+	}
+	return nil
+}
+
+// checkExistingAdmin handles a taken admin username. Another replica may have
+// just created the admin, otherwise the user is left as it is and the server
+// starts without an admin rather than promoting it.
+func (s Server) checkExistingAdmin(username string) error {
+	user, err := s.store.GetUserByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == models.RoleAdmin {
+		return nil
+	}
+
+	s.logger.Errorf("There is no admin user and the user %s has the role %s, no admin was created. "+
+		"Create one with etfctl user add --role admin or choose another admin username", username, user.Role)
+	return nil
+}
+
+// SetUserRole changes the role of the user. Requests are authorized with the
+// stored role, so the change takes effect immediately, also for issued tokens.
+func (s Server) SetUserRole(id int, role models.Role) error {
+	if !role.Valid() {
+		return fmt.Errorf("%w: unknown role %q", ErrInvalidInput, role)
+	}
+
+	return s.store.SetUserRole(id, role)
+}
+
+func (s Server) ListUsers() ([]models.User, error) {
+	return s.store.ListUsers()
+}
+
+func (s Server) GetUser(username string) (*models.User, error) {
+	return s.store.GetUserByUsername(username)
+}
+
+// SetUserDisabled enables or disables the user. Disabling also revokes all sessions of the user.
+func (s Server) SetUserDisabled(id int, disabled bool) error {
+	if err := s.store.SetUserDisabled(id, disabled); err != nil {
+		return err
+	}
+
+	if !disabled {
+		return nil
+	}
+
+	return s.store.RevokeUserSessions(id, "")
+}
+
+func (s Server) DeleteUser(id int) error {
+	return s.store.DeleteUser(id)
+}
+
+// ResetPassword sets a new password for the user without checking the old one
+// and revokes all sessions of the user.
+func (s Server) ResetPassword(id int, password string) error {
+	return s.setPassword(id, password, "")
+}
+
+// ChangePassword sets a new password for the user after verifying the current one.
+// All other sessions of the user are revoked, the current one stays active.
+func (s Server) ChangePassword(username, currentPassword, newPassword, currentSessionID string) error {
+	user, err := s.store.GetUserByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	if ok, _ := checkPassword(user.Password, currentPassword); !ok {
+		return ErrInvalidCredentials
+	}
+
+	return s.setPassword(user.ID, newPassword, currentSessionID)
+}
+
+func (s Server) setPassword(id int, password, keepSessionID string) error {
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	if err = s.store.UpdateUserPassword(id, hash); err != nil {
+		return err
+	}
+
+	return s.store.RevokeUserSessions(id, keepSessionID)
+}
+
+// StartSession creates a new session for the user and returns its ID and refresh token.
+func (s Server) StartSession(user *models.User, userAgent, ip string) (string, string, error) {
+	sessionID, err := randomID(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, secret, err := newRefreshToken(sessionID)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = s.store.CreateSession(models.Session{
+		ID:               sessionID,
+		UserID:           user.ID,
+		RefreshTokenHash: hashToken(secret),
+		UserAgent:        userAgent,
+		IP:               ip,
+	}, refreshTokenTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	return sessionID, refreshToken, nil
+}
+
+// RefreshSession exchanges a refresh token for a new one and returns the user and session it belongs to.
+// Presenting an already rotated refresh token revokes the whole session, since it means the token leaked.
+func (s Server) RefreshSession(refreshToken string) (*models.User, string, string, error) {
+	sessionID, secret, ok := parseRefreshToken(refreshToken)
+	if !ok {
+		return nil, "", "", ErrInvalidCredentials
+	}
+
+	newToken, newSecret, err := newRefreshToken(sessionID)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	rotated, err := s.store.RotateRefreshToken(sessionID, hashToken(secret), hashToken(newSecret), refreshTokenTTL)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, "", "", ErrInvalidCredentials
+		}
+		return nil, "", "", err
+	}
+
+	if !rotated {
+		if session.RevokedAt == nil {
+			s.logger.Warnf("Refresh token reuse detected for session %s, revoking it", sessionID)
+			if err := s.store.RevokeSession(session.UserID, sessionID); err != nil {
+				s.logger.Errorf("Could not revoke session %s: %v", sessionID, err)
+			}
+		}
+		return nil, "", "", ErrInvalidCredentials
+	}
+
+	user, err := s.store.GetUserByID(session.UserID)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if user.Disabled {
+		return nil, "", "", ErrUserDisabled
+	}
+
+	return user, sessionID, newToken, nil
+}
+
+// SessionUser returns the user owning the session, as long as tokens issued
+// for the session are still accepted.
+func (s Server) SessionUser(sessionID string) (*models.User, error) {
+	active, err := s.store.IsSessionActive(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !active {
+		return nil, ErrInvalidCredentials
+	}
+
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	user, err := s.store.GetUserByID(session.UserID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	return user, nil
+}
+
+// ListSessions returns the active sessions of the user and marks the current one.
+func (s Server) ListSessions(username, currentSessionID string) ([]models.Session, error) {
+	user, err := s.store.GetUserByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.store.ListActiveSessions(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes one session of the user.
+func (s Server) RevokeSession(username, sessionID string) error {
+	user, err := s.store.GetUserByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	return s.store.RevokeSession(user.ID, sessionID)
+}
+
+// RevokeAllSessions revokes every session of the user.
+func (s Server) RevokeAllSessions(username string) error {
+	user, err := s.store.GetUserByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	return s.store.RevokeUserSessions(user.ID, "")
+}
+
+// CreateAPIKey creates a new API key for the user. The scope defaults to the
+// user's role and cannot exceed it.
+func (s Server) CreateAPIKey(username string, req models.CreateAPIKeyRequest) (*models.CreatedAPIKey, error) {
+	user, err := s.store.GetUserByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+
+	if req.Scope == "" {
+		req.Scope = user.Role
+	}
+
+	if !req.Scope.Valid() || !user.Role.Includes(req.Scope) {
+		return nil, fmt.Errorf("%w: scope %q is not allowed", ErrInvalidInput, req.Scope)
+	}
+
+	if req.ExpiresInDays < 0 {
+		return nil, fmt.Errorf("%w: expires_in_days must not be negative", ErrInvalidInput)
+	}
+
+	plainKey, prefix, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.store.CreateAPIKey(models.APIKey{
+		UserID:  user.ID,
+		Name:    req.Name,
+		Prefix:  prefix,
+		KeyHash: hashToken(plainKey),
+		Scope:   req.Scope,
+	}, time.Duration(req.ExpiresInDays)*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CreatedAPIKey{APIKey: *key, Key: plainKey}, nil
+}
+
+func (s Server) ListAPIKeys(username string) ([]models.APIKey, error) {
+	user, err := s.store.GetUserByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.store.ListAPIKeys(user.ID)
+}
+
+func (s Server) RevokeAPIKey(username string, id int) error {
+	user, err := s.store.GetUserByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	return s.store.RevokeAPIKey(user.ID, id)
+}
+
+// AuthenticateAPIKey checks the API key and returns its owner and the key.
+// The key grants the lower of its scope and the owner's current role.
+func (s Server) AuthenticateAPIKey(plainKey string) (*models.User, *models.APIKey, error) {
+	prefix, ok := parseAPIKey(plainKey)
+	if !ok {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	key, err := s.store.GetActiveAPIKey(prefix)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil, ErrInvalidCredentials
+		}
+		return nil, nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashToken(plainKey))) != 1 {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	user, err := s.store.GetUserByID(key.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if user.Disabled {
+		return nil, nil, ErrUserDisabled
+	}
+
+	if !user.Role.Includes(key.Scope) {
+		key.Scope = user.Role
+	}
+
+	if err = s.store.TouchAPIKey(key.ID); err != nil {
+		s.logger.Errorf("Could not update last use of API key %d: %v", key.ID, err)
+	}
+
+	return user, key, nil
+}
+
+// RecordAudit queues an audit event for writing.
+func (s Server) RecordAudit(event models.AuditEvent) {
+	s.auditor.Record(event)
+}
+
+// ListAuditEvents returns the audit events matching the filter, newest first.
+func (s Server) ListAuditEvents(filter models.AuditFilter) ([]models.AuditEvent, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+
+	if filter.Limit > maxAuditLimit || filter.Offset < 0 {
+		return nil, fmt.Errorf("%w: limit must not exceed %d and offset must not be negative", ErrInvalidInput, maxAuditLimit)
+	}
+
+	return s.store.ListAuditEvents(filter)
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("%w: password must be at least %d characters", ErrInvalidInput, minPasswordLength)
+	}
+
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("%w: password must be at most %d bytes", ErrInvalidInput, maxPasswordLength)
+	}
 
-	return hex.EncodeToString(hashBytes)
+	return nil
 }
@@ -0,0 +1,762 @@
+package internal
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"awesomeProject/migrations"
+	"awesomeProject/models"
+)
+
+// SQLiteDatabase stores everything in a single SQLite file, so the server
+// can run locally without PostgreSQL. Timestamps are set from Go in UTC,
+// SQLite has no time zone aware date functions.
+type SQLiteDatabase struct {
+	db *sql.DB
+}
+
+// NewSQLiteDatabase opens or creates the SQLite database at path.
+func NewSQLiteDatabase(path string) (*SQLiteDatabase, error) {
+	// Foreign keys cascade deletes of users, the busy timeout lets the
+	// updater goroutines wait for each other instead of failing
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows a single writer at a time
+	db.SetMaxOpenConns(1)
+
+	return &SQLiteDatabase{db: db}, nil
+}
+
+// RunMigrations applies all pending migrations.
+func (d *SQLiteDatabase) RunMigrations() error {
+	m, err := d.newMigrate()
+	if err != nil {
+		return err
+	}
+
+	err = m.Up()
+	if err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	return nil
+}
+
+// RollbackMigrations reverts the given number of migrations.
+func (d *SQLiteDatabase) RollbackMigrations(steps int) error {
+	m, err := d.newMigrate()
+	if err != nil {
+		return err
+	}
+
+	err = m.Steps(-steps)
+	if err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	return nil
+}
+
+// MigrateTo applies or reverts migrations until the database is at the given version.
+func (d *SQLiteDatabase) MigrateTo(version uint) error {
+	m, err := d.newMigrate()
+	if err != nil {
+		return err
+	}
+
+	err = m.Migrate(version)
+	if err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	return nil
+}
+
+// ForceMigrationVersion records the version as applied and clears the dirty
+// flag without running a migration. It is meant for repairing the database by
+// hand after a migration failed half way, -1 records that none was applied.
+func (d *SQLiteDatabase) ForceMigrationVersion(version int) error {
+	m, err := d.newMigrate()
+	if err != nil {
+		return err
+	}
+
+	return m.Force(version)
+}
+
+// MigrationVersion returns the version of the last applied migration and
+// whether it failed half way. The version is 0 when no migration was applied.
+func (d *SQLiteDatabase) MigrationVersion() (uint, bool, error) {
+	return readMigrationVersion(d.db, "SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')")
+}
+
+// LatestMigrationVersion returns the version of the newest embedded migration.
+func (d *SQLiteDatabase) LatestMigrationVersion() (uint, error) {
+	return latestMigrationVersion(migrations.SQLite)
+}
+
+func (d *SQLiteDatabase) newMigrate() (*migrate.Migrate, error) {
+	src, err := iofs.New(migrations.SQLite, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := migratesqlite.WithInstance(d.db, &migratesqlite.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithInstance("iofs", src, "sqlite", driver)
+}
+
+// Upsert either updates an existing ETF record or creates a new one.
+func (d *SQLiteDatabase) Upsert(etf models.ETF) error {
+	_, err := d.db.Exec(upsertETFsQuery(1, 1, "$1"), upsertETFsArgs([]models.ETF{etf}, time.Now().UTC())...)
+	return err
+}
+
+// UpsertMany updates or creates all ETF records in a single transaction,
+// using one multi-row statement per upsertBatchRows records.
+func (d *SQLiteDatabase) UpsertMany(etfs []models.ETF) error {
+	etfs = dedupeETFs(etfs)
+	if len(etfs) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := upsertETFs(tx, etfs, "$1", now); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetAllIDs retrieves all available ETF IDs from the database.
+func (d *SQLiteDatabase) GetAllIDs() ([]string, error) {
+	rows, err := d.db.Query("SELECT id FROM etfs")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GetAll retrieves all ETFs ordered by ID.
+func (d *SQLiteDatabase) GetAll() ([]models.ETF, error) {
+	rows, err := d.db.Query("SELECT id, data, created_at, updated_at FROM etfs ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var etfs []models.ETF
+
+	for rows.Next() {
+		var etf models.ETF
+		if err := rows.Scan(&etf.ID, &etf.Data, &etf.CreatedAt, &etf.UpdatedAt); err != nil {
+			return nil, err
+		}
+		etfs = append(etfs, etf)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return etfs, nil
+}
+
+// GetByID retrieves an ETF by its ID.
+func (d *SQLiteDatabase) GetByID(id string) (*models.ETF, error) {
+	var etf models.ETF
+
+	err := d.db.QueryRow("SELECT id, data, created_at, updated_at FROM etfs WHERE id = $1", id).Scan(
+		&etf.ID,
+		&etf.Data,
+		&etf.CreatedAt,
+		&etf.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("ETF %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &etf, nil
+}
+
+// LastUpdatedAt returns when the most recently updated ETF was stored, nil when there is none.
+func (d *SQLiteDatabase) LastUpdatedAt() (*time.Time, error) {
+	var updatedAt sql.NullTime
+
+	err := d.db.QueryRow("SELECT updated_at FROM etfs WHERE updated_at IS NOT NULL ORDER BY updated_at DESC LIMIT 1").Scan(&updatedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	return nullTimePtr(updatedAt), nil
+}
+
+// ReplaceHoldings stores the full holdings of every ETF in a single transaction.
+func (d *SQLiteDatabase) ReplaceHoldings(holdings []models.FullHoldings) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := replaceHoldings(tx, holdings); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListHoldings returns a page of the full holdings of an ETF.
+func (d *SQLiteDatabase) ListHoldings(etfID string, limit, offset int) (*models.HoldingsPage, error) {
+	return listHoldings(d.db, "as_of", etfID, limit, offset)
+}
+
+// UpsertDistributions stores the distributions of every ETF in a single transaction.
+func (d *SQLiteDatabase) UpsertDistributions(distributions []models.ETFDistributions) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := upsertDistributions(tx, distributions); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListDistributions returns the distributions of an ETF within a date range.
+func (d *SQLiteDatabase) ListDistributions(etfID, from, to string) ([]models.Distribution, error) {
+	return listDistributions(d.db, "%s", etfID, from, to)
+}
+
+// StoreScraped stores the ETF records, their full holdings and their distributions in a single transaction.
+func (d *SQLiteDatabase) StoreScraped(etfs []models.ETF, holdings []models.FullHoldings, distributions []models.ETFDistributions) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = upsertETFs(tx, dedupeETFs(etfs), "$1", time.Now().UTC())
+	if err == nil {
+		err = replaceHoldings(tx, holdings)
+	}
+	if err == nil {
+		err = upsertDistributions(tx, distributions)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Ping checks that the database can be reached.
+func (d *SQLiteDatabase) Ping() error {
+	return d.db.Ping()
+}
+
+// sqliteUserColumns lists the users columns in the order expected by scanUser.
+// Time columns must not be wrapped in functions, the driver only parses the
+// values of columns declared as DATETIME.
+const sqliteUserColumns = "id, username, password, COALESCE(email, ''), role, disabled, last_login_at, created_at, updated_at"
+
+// CreateUser inserts a new user and returns it with the generated fields set.
+func (d *SQLiteDatabase) CreateUser(user models.User) (*models.User, error) {
+	now := time.Now().UTC()
+
+	row := d.db.QueryRow(
+		"INSERT INTO users (username, password, email, role, disabled, created_at, updated_at) "+
+			"VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $6) RETURNING "+sqliteUserColumns,
+		user.Username, user.Password, user.Email, user.Role, user.Disabled, now,
+	)
+
+	created, err := scanUser(row)
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return nil, fmt.Errorf("user %w", ErrAlreadyExists)
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ListUsers retrieves all users ordered by ID.
+func (d *SQLiteDatabase) ListUsers() ([]models.User, error) {
+	rows, err := d.db.Query("SELECT " + sqliteUserColumns + " FROM users ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetUserByID retrieves a user by ID.
+func (d *SQLiteDatabase) GetUserByID(id int) (*models.User, error) {
+	user, err := scanUser(d.db.QueryRow("SELECT "+sqliteUserColumns+" FROM users WHERE id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetUserByUsername retrieves a user by username.
+func (d *SQLiteDatabase) GetUserByUsername(username string) (*models.User, error) {
+	user, err := scanUser(d.db.QueryRow("SELECT "+sqliteUserColumns+" FROM users WHERE username = $1", username))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateUserPassword replaces the stored password hash of a user.
+func (d *SQLiteDatabase) UpdateUserPassword(id int, password string) error {
+	res, err := d.db.Exec("UPDATE users SET password = $1, updated_at = $2 WHERE id = $3", password, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res, "user")
+}
+
+// ReplaceUserPassword replaces the stored password hash of a user while it still is current.
+func (d *SQLiteDatabase) ReplaceUserPassword(id int, current, password string) (bool, error) {
+	res, err := d.db.Exec("UPDATE users SET password = $1, updated_at = $2 WHERE id = $3 AND password = $4", password, time.Now().UTC(), id, current)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+// SetUserDisabled enables or disables a user.
+func (d *SQLiteDatabase) SetUserDisabled(id int, disabled bool) error {
+	res, err := d.db.Exec("UPDATE users SET disabled = $1, updated_at = $2 WHERE id = $3", disabled, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res, "user")
+}
+
+// SetUserRole changes the role of a user.
+func (d *SQLiteDatabase) SetUserRole(id int, role models.Role) error {
+	res, err := d.db.Exec("UPDATE users SET role = $1, updated_at = $2 WHERE id = $3", role, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res, "user")
+}
+
+// UpdateLastLogin sets the last login time of a user to now.
+func (d *SQLiteDatabase) UpdateLastLogin(id int) error {
+	_, err := d.db.Exec("UPDATE users SET last_login_at = $1 WHERE id = $2", time.Now().UTC(), id)
+	return err
+}
+
+// DeleteUser removes a user.
+func (d *SQLiteDatabase) DeleteUser(id int) error {
+	res, err := d.db.Exec("DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res, "user")
+}
+
+// sqliteSessionColumns lists the sessions columns in the order expected by scanSession.
+const sqliteSessionColumns = "id, user_id, refresh_token_hash, COALESCE(user_agent, ''), COALESCE(ip, ''), created_at, last_used_at, expires_at, revoked_at"
+
+// CreateSession stores a new session that expires after the given TTL.
+func (d *SQLiteDatabase) CreateSession(session models.Session, ttl time.Duration) error {
+	now := time.Now().UTC()
+
+	_, err := d.db.Exec(
+		"INSERT INTO sessions (id, user_id, refresh_token_hash, user_agent, ip, created_at, last_used_at, expires_at) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $6, $7)",
+		session.ID, session.UserID, session.RefreshTokenHash, session.UserAgent, session.IP, now, now.Add(ttl),
+	)
+	return err
+}
+
+// RotateRefreshToken replaces the refresh token hash of an active session and extends it by the TTL.
+// It returns false when the session is unknown, revoked, expired or the old hash does not match.
+func (d *SQLiteDatabase) RotateRefreshToken(id, oldHash, newHash string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+
+	res, err := d.db.Exec(
+		"UPDATE sessions SET refresh_token_hash = $1, last_used_at = $2, expires_at = $3 "+
+			"WHERE id = $4 AND refresh_token_hash = $5 AND revoked_at IS NULL AND expires_at > $2",
+		newHash, now, now.Add(ttl), id, oldHash,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// GetSession retrieves a session by ID.
+func (d *SQLiteDatabase) GetSession(id string) (*models.Session, error) {
+	session, err := scanSession(d.db.QueryRow("SELECT "+sqliteSessionColumns+" FROM sessions WHERE id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("session %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// ListActiveSessions retrieves the sessions of a user that are neither revoked nor expired.
+func (d *SQLiteDatabase) ListActiveSessions(userID int) ([]models.Session, error) {
+	rows, err := d.db.Query(
+		"SELECT "+sqliteSessionColumns+" FROM sessions "+
+			"WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2 ORDER BY last_used_at DESC",
+		userID, time.Now().UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// IsSessionActive reports whether the session is neither revoked nor expired and its user is enabled.
+func (d *SQLiteDatabase) IsSessionActive(id string) (bool, error) {
+	var active bool
+	err := d.db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM sessions s JOIN users u ON u.id = s.user_id "+
+			"WHERE s.id = $1 AND s.revoked_at IS NULL AND s.expires_at > $2 AND NOT u.disabled)",
+		id, time.Now().UTC(),
+	).Scan(&active)
+	if err != nil {
+		return false, err
+	}
+	return active, nil
+}
+
+// RevokeSession revokes a single session of a user.
+func (d *SQLiteDatabase) RevokeSession(userID int, id string) error {
+	res, err := d.db.Exec(
+		"UPDATE sessions SET revoked_at = $1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL",
+		time.Now().UTC(), id, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res, "session")
+}
+
+// RevokeUserSessions revokes every session of a user except the one with the given ID.
+// Pass an empty ID to revoke all of them.
+func (d *SQLiteDatabase) RevokeUserSessions(userID int, exceptID string) error {
+	_, err := d.db.Exec(
+		"UPDATE sessions SET revoked_at = $1 WHERE user_id = $2 AND id <> $3 AND revoked_at IS NULL",
+		time.Now().UTC(), userID, exceptID,
+	)
+	return err
+}
+
+// CreateAPIKey stores a new API key. A zero TTL creates a key that never expires.
+func (d *SQLiteDatabase) CreateAPIKey(key models.APIKey, ttl time.Duration) (*models.APIKey, error) {
+	now := time.Now().UTC()
+
+	var expiresAt sql.NullTime
+	if ttl > 0 {
+		expiresAt = sql.NullTime{Time: now.Add(ttl), Valid: true}
+	}
+
+	row := d.db.QueryRow(
+		"INSERT INTO api_keys (user_id, name, prefix, key_hash, scope, created_at, expires_at) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING "+apiKeyColumns,
+		key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scope, now, expiresAt,
+	)
+
+	return scanAPIKey(row)
+}
+
+// ListAPIKeys retrieves all API keys of a user, including revoked and expired ones.
+func (d *SQLiteDatabase) ListAPIKeys(userID int) ([]models.APIKey, error) {
+	rows, err := d.db.Query("SELECT "+apiKeyColumns+" FROM api_keys WHERE user_id = $1 ORDER BY id", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// GetActiveAPIKey retrieves an API key by its prefix if it is neither revoked nor expired.
+func (d *SQLiteDatabase) GetActiveAPIKey(prefix string) (*models.APIKey, error) {
+	key, err := scanAPIKey(d.db.QueryRow(
+		"SELECT "+apiKeyColumns+" FROM api_keys "+
+			"WHERE prefix = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2)",
+		prefix, time.Now().UTC(),
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("api key %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// TouchAPIKey records that an API key has just been used.
+func (d *SQLiteDatabase) TouchAPIKey(id int) error {
+	_, err := d.db.Exec("UPDATE api_keys SET last_used_at = $1 WHERE id = $2", time.Now().UTC(), id)
+	return err
+}
+
+// RevokeAPIKey revokes an API key of a user.
+func (d *SQLiteDatabase) RevokeAPIKey(userID, id int) error {
+	res, err := d.db.Exec(
+		"UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL",
+		time.Now().UTC(), id, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res, "api key")
+}
+
+// IncrementQuota counts a request of the client for the day (YYYY-MM-DD) and returns the new count.
+func (d *SQLiteDatabase) IncrementQuota(clientKey string, day string) (int, error) {
+	var count int
+	err := d.db.QueryRow(
+		"INSERT INTO rate_quotas (client_key, day, requests) VALUES ($1, $2, 1) "+
+			"ON CONFLICT (client_key, day) DO UPDATE SET requests = rate_quotas.requests + 1 RETURNING requests",
+		clientKey, day,
+	).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// DeleteQuotasBefore removes the quota counters of days before the given day (YYYY-MM-DD).
+func (d *SQLiteDatabase) DeleteQuotasBefore(day string) error {
+	_, err := d.db.Exec("DELETE FROM rate_quotas WHERE day < $1", day)
+	return err
+}
+
+// InsertAuditEvent stores an audit event.
+func (d *SQLiteDatabase) InsertAuditEvent(event models.AuditEvent) error {
+	var details sql.NullString
+	if len(event.Details) > 0 {
+		b, err := json.Marshal(event.Details)
+		if err != nil {
+			return err
+		}
+		details = sql.NullString{String: string(b), Valid: true}
+	}
+
+	_, err := d.db.Exec(
+		"INSERT INTO audit_events (occurred_at, event_type, username, ip, request_id, method, path, status, resource, details) "+
+			"VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, 0), NULLIF($9, ''), $10)",
+		event.OccurredAt.UTC(), event.Type, event.Username, event.IP, event.RequestID,
+		event.Method, event.Path, event.Status, event.Resource, details,
+	)
+	return err
+}
+
+// ListAuditEvents retrieves audit events matching the filter, newest first.
+func (d *SQLiteDatabase) ListAuditEvents(filter models.AuditFilter) ([]models.AuditEvent, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Username != "" {
+		addCondition("username = $%d", filter.Username)
+	}
+	if filter.Type != "" {
+		addCondition("event_type = $%d", filter.Type)
+	}
+	if filter.Resource != "" {
+		addCondition("resource = $%d", filter.Resource)
+	}
+	if filter.RequestID != "" {
+		addCondition("request_id = $%d", filter.RequestID)
+	}
+	if filter.From != nil {
+		addCondition("occurred_at >= $%d", filter.From.UTC())
+	}
+	if filter.To != nil {
+		addCondition("occurred_at < $%d", filter.To.UTC())
+	}
+
+	query := "SELECT id, occurred_at, event_type, COALESCE(username, ''), COALESCE(ip, ''), COALESCE(request_id, ''), " +
+		"COALESCE(method, ''), COALESCE(path, ''), COALESCE(status, 0), COALESCE(resource, ''), details FROM audit_events"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY occurred_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.AuditEvent{}
+
+	for rows.Next() {
+		var event models.AuditEvent
+		var details sql.NullString
+
+		err := rows.Scan(
+			&event.ID,
+			&event.OccurredAt,
+			&event.Type,
+			&event.Username,
+			&event.IP,
+			&event.RequestID,
+			&event.Method,
+			&event.Path,
+			&event.Status,
+			&event.Resource,
+			&details,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if details.Valid && details.String != "" {
+			if err := json.Unmarshal([]byte(details.String), &event.Details); err != nil {
+				return nil, err
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// isSQLiteUniqueViolation reports whether the error is a SQLite unique or primary key constraint violation.
+func isSQLiteUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
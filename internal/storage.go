@@ -0,0 +1,427 @@
+package internal
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4/source"
+
+	"awesomeProject/models"
+)
+
+// ETFStore persists the scraped ETF data. UpsertMany stores all records in a
+// single transaction, when an ID repeats the last record wins.
+type ETFStore interface {
+	Upsert(etf models.ETF) error
+	UpsertMany(etfs []models.ETF) error
+	GetAllIDs() ([]string, error)
+	GetAll() ([]models.ETF, error)
+	GetByID(id string) (*models.ETF, error)
+	LastUpdatedAt() (*time.Time, error)
+
+	// ReplaceHoldings stores the full holdings of every ETF in a single
+	// transaction, replacing the positions stored for it before.
+	ReplaceHoldings(holdings []models.FullHoldings) error
+	// ListHoldings returns limit positions of the full holdings of an ETF after
+	// offset in the order of its holdings file, and ErrNotFound when none are stored.
+	// A negative limit or offset returns ErrInvalidInput.
+	ListHoldings(etfID string, limit, offset int) (*models.HoldingsPage, error)
+
+	// UpsertDistributions stores the distributions of every ETF in a single
+	// transaction. Distributions stored before are kept, the fund pages only
+	// show the recent ones.
+	UpsertDistributions(distributions []models.ETFDistributions) error
+	// ListDistributions returns the distributions of an ETF with an ex-date
+	// between from and to, formatted as YYYY-MM-DD and unbounded when empty,
+	// the newest first.
+	ListDistributions(etfID, from, to string) ([]models.Distribution, error)
+
+	// StoreScraped runs UpsertMany, ReplaceHoldings and UpsertDistributions in
+	// a single transaction, so either all of the data is stored or none.
+	StoreScraped(etfs []models.ETF, holdings []models.FullHoldings, distributions []models.ETFDistributions) error
+}
+
+// UserStore persists users. Methods changing a single user return ErrNotFound
+// when it does not exist, CreateUser returns ErrAlreadyExists for taken usernames.
+type UserStore interface {
+	CreateUser(user models.User) (*models.User, error)
+	ListUsers() ([]models.User, error)
+	GetUserByID(id int) (*models.User, error)
+	GetUserByUsername(username string) (*models.User, error)
+	UpdateUserPassword(id int, password string) error
+	// ReplaceUserPassword replaces the password hash of a user only while it
+	// still is current, and reports whether it did.
+	ReplaceUserPassword(id int, current, password string) (bool, error)
+	SetUserDisabled(id int, disabled bool) error
+	SetUserRole(id int, role models.Role) error
+	UpdateLastLogin(id int) error
+	DeleteUser(id int) error
+}
+
+// SessionStore persists the login sessions backing refresh tokens.
+type SessionStore interface {
+	CreateSession(session models.Session, ttl time.Duration) error
+	RotateRefreshToken(id, oldHash, newHash string, ttl time.Duration) (bool, error)
+	GetSession(id string) (*models.Session, error)
+	ListActiveSessions(userID int) ([]models.Session, error)
+	IsSessionActive(id string) (bool, error)
+	RevokeSession(userID int, id string) error
+	RevokeUserSessions(userID int, exceptID string) error
+}
+
+// APIKeyStore persists API keys.
+type APIKeyStore interface {
+	CreateAPIKey(key models.APIKey, ttl time.Duration) (*models.APIKey, error)
+	ListAPIKeys(userID int) ([]models.APIKey, error)
+	GetActiveAPIKey(prefix string) (*models.APIKey, error)
+	TouchAPIKey(id int) error
+	RevokeAPIKey(userID, id int) error
+}
+
+// Store is everything the server keeps in its database. It is implemented by
+// Database for PostgreSQL, SQLiteDatabase for single-binary local use and
+// MemoryStore for tests.
+type Store interface {
+	ETFStore
+	UserStore
+	SessionStore
+	APIKeyStore
+	QuotaCounter
+	AuditStore
+	ListAuditEvents(filter models.AuditFilter) ([]models.AuditEvent, error)
+	Ping() error
+}
+
+// Migrator is implemented by stores with a schema managed by migrations.
+// The migrations are embedded in the binary, see package migrations.
+type Migrator interface {
+	RunMigrations() error
+	RollbackMigrations(steps int) error
+	MigrateTo(version uint) error
+	ForceMigrationVersion(version int) error
+	MigrationVersion() (uint, bool, error)
+	LatestMigrationVersion() (uint, error)
+}
+
+// Supported values of Config.DBDriver.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+// OpenStore connects to the configured store.
+func (c Config) OpenStore() (Store, error) {
+	switch c.DBDriver {
+	case DriverPostgres:
+		return NewDatabase(c.DBHost, c.DBPort, c.DBUser, c.DBPassword, c.DBName)
+	case DriverSQLite:
+		return NewSQLiteDatabase(c.DBPath)
+	case DriverMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", c.DBDriver)
+	}
+}
+
+// upsertBatchRows is the number of rows per multi-row INSERT of UpsertMany,
+// it keeps the statements well below the parameter limits of both databases.
+const upsertBatchRows = 500
+
+// dedupeETFs drops all but the last record of every ID. A single
+// INSERT ... ON CONFLICT statement must not touch the same row twice.
+func dedupeETFs(etfs []models.ETF) []models.ETF {
+	last := make(map[string]int, len(etfs))
+	for i, etf := range etfs {
+		last[etf.ID] = i
+	}
+
+	unique := make([]models.ETF, 0, len(last))
+	for i, etf := range etfs {
+		if last[etf.ID] == i {
+			unique = append(unique, etf)
+		}
+	}
+
+	return unique
+}
+
+// upsertETFs runs UpsertMany in tx for both SQL stores. timestamp and leading
+// are passed on to upsertETFsQuery and upsertETFsArgs.
+func upsertETFs(tx *sql.Tx, etfs []models.ETF, timestamp string, leading ...interface{}) error {
+	for start := 0; start < len(etfs); start += upsertBatchRows {
+		end := start + upsertBatchRows
+		if end > len(etfs) {
+			end = len(etfs)
+		}
+		batch := etfs[start:end]
+
+		_, err := tx.Exec(upsertETFsQuery(len(batch), len(leading), timestamp), upsertETFsArgs(batch, leading...)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// upsertETFsQuery builds an INSERT ... ON CONFLICT statement for rows ETFs.
+// Every row binds its id and data to the next two parameters after offset,
+// timestamp is the SQL expression for created_at and updated_at.
+func upsertETFsQuery(rows, offset int, timestamp string) string {
+	values := make([]string, rows)
+	for i := range values {
+		values[i] = fmt.Sprintf("($%d, $%d, %s, %s)", offset+2*i+1, offset+2*i+2, timestamp, timestamp)
+	}
+
+	return "INSERT INTO etfs (id, data, created_at, updated_at) VALUES " + strings.Join(values, ", ") +
+		" ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data, updated_at = EXCLUDED.updated_at"
+}
+
+// upsertETFsArgs returns the parameters of upsertETFsQuery for etfs.
+func upsertETFsArgs(etfs []models.ETF, leading ...interface{}) []interface{} {
+	args := append([]interface{}{}, leading...)
+	for _, etf := range etfs {
+		args = append(args, etf.ID, etf.Data)
+	}
+	return args
+}
+
+// holdingColumns are the columns of etf_holdings bound by insertHoldingsQuery.
+const holdingColumns = "etf_id, position, as_of, name, ticker, identifier, sedol, sector, shares_held, market_value, weight, currency"
+
+// holdingParams is the number of columns in holdingColumns.
+const holdingParams = 12
+
+// insertHoldingsQuery builds a multi-row INSERT into etf_holdings for rows positions.
+func insertHoldingsQuery(rows int) string {
+	values := make([]string, rows)
+	for i := range values {
+		params := make([]string, holdingParams)
+		for j := range params {
+			params[j] = fmt.Sprintf("$%d", i*holdingParams+j+1)
+		}
+		values[i] = "(" + strings.Join(params, ", ") + ")"
+	}
+
+	return "INSERT INTO etf_holdings (" + holdingColumns + ") VALUES " + strings.Join(values, ", ")
+}
+
+// insertHoldingsArgs returns the parameters of insertHoldingsQuery for the
+// positions of holdings starting at offset.
+func insertHoldingsArgs(holdings models.FullHoldings, positions []models.FullHolding, offset int) []interface{} {
+	var asOf interface{}
+	if holdings.AsOf != "" {
+		asOf = holdings.AsOf
+	}
+
+	args := make([]interface{}, 0, len(positions)*holdingParams)
+	for i, h := range positions {
+		args = append(args, holdings.Ticker, offset+i, asOf, h.Name, nullString(h.Ticker), nullString(h.Identifier),
+			nullString(h.SEDOL), nullString(h.Sector), h.SharesHeld, h.MarketValue, h.Weight, nullString(h.Currency))
+	}
+	return args
+}
+
+// replaceHoldings runs ReplaceHoldings in tx for both SQL stores.
+func replaceHoldings(tx *sql.Tx, holdings []models.FullHoldings) error {
+	for _, etfHoldings := range holdings {
+		if _, err := tx.Exec("DELETE FROM etf_holdings WHERE etf_id = $1", etfHoldings.Ticker); err != nil {
+			return err
+		}
+
+		for start := 0; start < len(etfHoldings.Holdings); start += upsertBatchRows {
+			end := start + upsertBatchRows
+			if end > len(etfHoldings.Holdings) {
+				end = len(etfHoldings.Holdings)
+			}
+			batch := etfHoldings.Holdings[start:end]
+
+			if _, err := tx.Exec(insertHoldingsQuery(len(batch)), insertHoldingsArgs(etfHoldings, batch, start)...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// listHoldings runs ListHoldings for both SQL stores, asOf is the SQL
+// expression reading the as_of column as YYYY-MM-DD.
+func listHoldings(db *sql.DB, asOf, etfID string, limit, offset int) (*models.HoldingsPage, error) {
+	if limit < 0 || offset < 0 {
+		return nil, fmt.Errorf("%w: negative limit or offset", ErrInvalidInput)
+	}
+
+	page := models.HoldingsPage{Ticker: etfID, Holdings: []models.FullHolding{}}
+
+	var date sql.NullString
+	err := db.QueryRow("SELECT COUNT(*), MAX("+asOf+") FROM etf_holdings WHERE etf_id = $1", etfID).Scan(&page.Total, &date)
+	if err != nil {
+		return nil, err
+	}
+	if page.Total == 0 {
+		return nil, fmt.Errorf("holdings %w", ErrNotFound)
+	}
+	page.AsOf = date.String
+
+	rows, err := db.Query("SELECT name, ticker, identifier, sedol, sector, shares_held, market_value, weight, currency "+
+		"FROM etf_holdings WHERE etf_id = $1 ORDER BY position LIMIT $2 OFFSET $3", etfID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			h                                           models.FullHolding
+			ticker, identifier, sedol, sector, currency sql.NullString
+			sharesHeld, marketValue, weight             sql.NullFloat64
+		)
+		if err := rows.Scan(&h.Name, &ticker, &identifier, &sedol, &sector, &sharesHeld, &marketValue, &weight, &currency); err != nil {
+			return nil, err
+		}
+
+		h.Ticker, h.Identifier, h.SEDOL, h.Sector, h.Currency = ticker.String, identifier.String, sedol.String, sector.String, currency.String
+		h.SharesHeld, h.MarketValue, h.Weight = nullFloatPtr(sharesHeld), nullFloatPtr(marketValue), nullFloatPtr(weight)
+
+		page.Holdings = append(page.Holdings, h)
+	}
+
+	return &page, rows.Err()
+}
+
+// upsertDistributionQuery stores a distribution, updated_at is reset by its default.
+const upsertDistributionQuery = "INSERT INTO etf_distributions (etf_id, ex_date, distribution_type, record_date, pay_date, amount) " +
+	"VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (etf_id, ex_date, distribution_type) DO UPDATE SET " +
+	"record_date = EXCLUDED.record_date, pay_date = EXCLUDED.pay_date, amount = EXCLUDED.amount, updated_at = EXCLUDED.updated_at"
+
+// upsertDistributions runs UpsertDistributions in tx for both SQL stores.
+func upsertDistributions(tx *sql.Tx, distributions []models.ETFDistributions) error {
+	stmt, err := tx.Prepare(upsertDistributionQuery)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, etfDistributions := range distributions {
+		for _, d := range etfDistributions.Distributions {
+			_, err := stmt.Exec(etfDistributions.Ticker, d.ExDate, d.Type, nullString(d.RecordDate), nullString(d.PayDate), d.Amount)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// listDistributions runs ListDistributions for both SQL stores, dateColumn
+// is the format of the SQL expression reading a date column as YYYY-MM-DD.
+func listDistributions(db *sql.DB, dateColumn, etfID, from, to string) ([]models.Distribution, error) {
+	query := fmt.Sprintf("SELECT %s, distribution_type, %s, %s, amount FROM etf_distributions WHERE etf_id = $1",
+		fmt.Sprintf(dateColumn, "ex_date"), fmt.Sprintf(dateColumn, "record_date"), fmt.Sprintf(dateColumn, "pay_date"))
+	args := []interface{}{etfID}
+
+	if from != "" {
+		args = append(args, from)
+		query += fmt.Sprintf(" AND ex_date >= $%d", len(args))
+	}
+	if to != "" {
+		args = append(args, to)
+		query += fmt.Sprintf(" AND ex_date <= $%d", len(args))
+	}
+
+	rows, err := db.Query(query+" ORDER BY ex_date DESC, distribution_type", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	distributions := []models.Distribution{}
+	for rows.Next() {
+		var (
+			d                   models.Distribution
+			recordDate, payDate sql.NullString
+		)
+		if err := rows.Scan(&d.ExDate, &d.Type, &recordDate, &payDate, &d.Amount); err != nil {
+			return nil, err
+		}
+		d.RecordDate, d.PayDate = recordDate.String, payDate.String
+
+		distributions = append(distributions, d)
+	}
+
+	return distributions, rows.Err()
+}
+
+// nullString stores empty strings as NULL.
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullFloatPtr(f sql.NullFloat64) *float64 {
+	if !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
+
+// readMigrationVersion reads the version golang-migrate records in the
+// schema_migrations table. Asking a migrate instance instead would take a
+// connection out of the pool for good, which adds up for the readiness probe.
+// tableExists is a query telling whether the table was created yet.
+func readMigrationVersion(db *sql.DB, tableExists string) (uint, bool, error) {
+	var exists bool
+	if err := db.QueryRow(tableExists).Scan(&exists); err != nil {
+		return 0, false, err
+	}
+	if !exists {
+		return 0, false, nil
+	}
+
+	var version int64
+	var dirty bool
+
+	err := db.QueryRow("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err == sql.ErrNoRows || err == nil && version < 0 {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return uint(version), dirty, nil
+}
+
+// latestMigrationVersion returns the highest version of the migrations in fsys,
+// the version the database is at once all of them were applied.
+func latestMigrationVersion(fsys fs.FS) (uint, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		m, err := source.DefaultParse(entry.Name())
+		if err != nil {
+			continue
+		}
+		if m.Version > latest {
+			latest = m.Version
+		}
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,361 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"awesomeProject/models"
+)
+
+// testStores runs test against every Store that works without a server.
+func testStores(t *testing.T, test func(t *testing.T, store Store)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, NewMemoryStore())
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		store, err := NewSQLiteDatabase(filepath.Join(t.TempDir(), "etf.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.RunMigrations(); err != nil {
+			t.Fatalf("RunMigrations: %v", err)
+		}
+		test(t, store)
+	})
+}
+
+func TestStoreUpsertETFs(t *testing.T) {
+	testStores(t, func(t *testing.T, store Store) {
+		if last, err := store.LastUpdatedAt(); err != nil || last != nil {
+			t.Fatalf("LastUpdatedAt of an empty store = %v, %v", last, err)
+		}
+
+		// The last record of a repeated ID wins
+		err := store.UpsertMany([]models.ETF{
+			{ID: "SPY", Data: []byte(`{"name":"SPY","description":"old"}`)},
+			{ID: "QQQ", Data: []byte(`{"name":"QQQ"}`)},
+			{ID: "SPY", Data: []byte(`{"name":"SPY","description":"new"}`)},
+		})
+		if err != nil {
+			t.Fatalf("UpsertMany: %v", err)
+		}
+
+		etf, err := store.GetByID("SPY")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if string(etf.Data) != `{"name":"SPY","description":"new"}` {
+			t.Errorf("GetByID data = %s", etf.Data)
+		}
+		created := etf.CreatedAt
+
+		if err := store.Upsert(models.ETF{ID: "SPY", Data: []byte(`{"name":"SPY","description":"updated"}`)}); err != nil {
+			t.Fatalf("Upsert: %v", err)
+		}
+		etf, err = store.GetByID("SPY")
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if string(etf.Data) != `{"name":"SPY","description":"updated"}` {
+			t.Errorf("GetByID data after Upsert = %s", etf.Data)
+		}
+		if !etf.CreatedAt.Equal(created) {
+			t.Errorf("Upsert changed created_at from %v to %v", created, etf.CreatedAt)
+		}
+
+		if _, err := store.GetByID("NONE"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetByID of an unknown ETF = %v, want ErrNotFound", err)
+		}
+
+		ids, err := store.GetAllIDs()
+		if err != nil {
+			t.Fatalf("GetAllIDs: %v", err)
+		}
+		sort.Strings(ids)
+		if !reflect.DeepEqual(ids, []string{"QQQ", "SPY"}) {
+			t.Errorf("GetAllIDs = %v", ids)
+		}
+
+		all, err := store.GetAll()
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		if len(all) != 2 || all[0].ID != "QQQ" || all[1].ID != "SPY" {
+			t.Errorf("GetAll = %v, want QQQ and SPY ordered by ID", all)
+		}
+
+		if last, err := store.LastUpdatedAt(); err != nil || last == nil || time.Since(*last) > time.Minute {
+			t.Errorf("LastUpdatedAt = %v, %v", last, err)
+		}
+	})
+}
+
+func TestStoreHoldings(t *testing.T) {
+	testStores(t, func(t *testing.T, store Store) {
+		seedTestETFs(t, store)
+
+		page, err := store.ListHoldings("SPY", 2, 0)
+		if err != nil {
+			t.Fatalf("ListHoldings: %v", err)
+		}
+		weight := 7.1
+		want := []models.FullHolding{{Name: "Apple Inc.", Ticker: "AAPL", Weight: &weight}, {Name: "Microsoft Corporation", Ticker: "MSFT"}}
+		if page.Total != 3 || page.AsOf != "2026-10-16" || !reflect.DeepEqual(page.Holdings, want) {
+			t.Errorf("first page = %+v", page)
+		}
+
+		page, err = store.ListHoldings("SPY", 2, 2)
+		if err != nil {
+			t.Fatalf("ListHoldings: %v", err)
+		}
+		if page.Total != 3 || len(page.Holdings) != 1 || page.Holdings[0].Ticker != "NVDA" {
+			t.Errorf("second page = %+v", page)
+		}
+
+		page, err = store.ListHoldings("SPY", 2, 4)
+		if err != nil {
+			t.Fatalf("ListHoldings: %v", err)
+		}
+		if page.Total != 3 || page.Holdings == nil || len(page.Holdings) != 0 {
+			t.Errorf("page after the last position = %+v", page)
+		}
+
+		// Replacing drops the positions stored before
+		shares, value := 1000.0, 2.5e6
+		replaced := models.FullHolding{
+			Name: "Cash", Identifier: "US0000000000", SEDOL: "0000000", Sector: "Cash",
+			SharesHeld: &shares, MarketValue: &value, Currency: "USD",
+		}
+		err = store.ReplaceHoldings([]models.FullHoldings{{Ticker: "SPY", Holdings: []models.FullHolding{replaced}}})
+		if err != nil {
+			t.Fatalf("ReplaceHoldings: %v", err)
+		}
+		page, err = store.ListHoldings("SPY", 10, 0)
+		if err != nil {
+			t.Fatalf("ListHoldings: %v", err)
+		}
+		if page.Total != 1 || page.AsOf != "" || !reflect.DeepEqual(page.Holdings, []models.FullHolding{replaced}) {
+			t.Errorf("replaced holdings = %+v", page)
+		}
+
+		if _, err := store.ListHoldings("SPY", 10, -10); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("ListHoldings with a negative offset = %v, want ErrInvalidInput", err)
+		}
+
+		if _, err := store.ListHoldings("QQQ", 10, 0); !errors.Is(err, ErrNotFound) {
+			t.Errorf("ListHoldings without holdings = %v, want ErrNotFound", err)
+		}
+
+		err = store.ReplaceHoldings([]models.FullHoldings{{Ticker: "NONE", Holdings: []models.FullHolding{{Name: "Cash"}}}})
+		if err == nil {
+			t.Error("ReplaceHoldings of an unknown ETF succeeded")
+		}
+	})
+}
+
+func TestStoreDistributions(t *testing.T) {
+	testStores(t, func(t *testing.T, store Store) {
+		if err := store.UpsertMany([]models.ETF{{ID: "SPY", Data: []byte(`{"name":"SPY"}`)}}); err != nil {
+			t.Fatalf("UpsertMany: %v", err)
+		}
+
+		err := store.UpsertDistributions([]models.ETFDistributions{{Ticker: "SPY", Distributions: []models.Distribution{
+			{ExDate: "2024-03-15", RecordDate: "2024-03-18", PayDate: "2024-04-30", Amount: 1.59},
+			{ExDate: "2024-06-21", Amount: 1.75},
+		}}})
+		if err != nil {
+			t.Fatalf("UpsertDistributions: %v", err)
+		}
+
+		// A later run only sees the recent distributions, the older ones are kept
+		err = store.UpsertDistributions([]models.ETFDistributions{{Ticker: "SPY", Distributions: []models.Distribution{
+			{ExDate: "2024-06-21", Amount: 1.76},
+			{ExDate: "2024-12-20", Amount: 2.7, Type: "Capital Gains"},
+			{ExDate: "2024-12-20", Amount: 1.97, Type: "Income"},
+		}}})
+		if err != nil {
+			t.Fatalf("UpsertDistributions: %v", err)
+		}
+
+		distributions, err := store.ListDistributions("SPY", "", "")
+		if err != nil {
+			t.Fatalf("ListDistributions: %v", err)
+		}
+		want := []models.Distribution{
+			{ExDate: "2024-12-20", Amount: 2.7, Type: "Capital Gains"},
+			{ExDate: "2024-12-20", Amount: 1.97, Type: "Income"},
+			{ExDate: "2024-06-21", Amount: 1.76},
+			{ExDate: "2024-03-15", RecordDate: "2024-03-18", PayDate: "2024-04-30", Amount: 1.59},
+		}
+		if !reflect.DeepEqual(distributions, want) {
+			t.Errorf("ListDistributions = %+v, want %+v", distributions, want)
+		}
+
+		distributions, err = store.ListDistributions("SPY", "2024-06-01", "2024-06-30")
+		if err != nil {
+			t.Fatalf("ListDistributions: %v", err)
+		}
+		if !reflect.DeepEqual(distributions, want[2:3]) {
+			t.Errorf("ListDistributions between dates = %+v", distributions)
+		}
+
+		distributions, err = store.ListDistributions("QQQ", "", "")
+		if err != nil || distributions == nil || len(distributions) != 0 {
+			t.Errorf("ListDistributions without distributions = %v, %v", distributions, err)
+		}
+
+		err = store.UpsertDistributions([]models.ETFDistributions{{Ticker: "NONE", Distributions: []models.Distribution{{ExDate: "2024-03-15"}}}})
+		if err == nil {
+			t.Error("UpsertDistributions of an unknown ETF succeeded")
+		}
+	})
+}
+
+func TestStoreScraped(t *testing.T) {
+	testStores(t, func(t *testing.T, store Store) {
+		etfs := []models.ETF{{ID: "SPY", Data: []byte(`{"name":"SPY"}`)}}
+		holdings := []models.FullHoldings{{Ticker: "SPY", Holdings: []models.FullHolding{{Name: "Apple Inc."}}}}
+		distributions := []models.ETFDistributions{{Ticker: "SPY", Distributions: []models.Distribution{{ExDate: "2024-03-15", Amount: 1.59}}}}
+
+		if err := store.StoreScraped(etfs, holdings, distributions); err != nil {
+			t.Fatalf("StoreScraped: %v", err)
+		}
+
+		if _, err := store.GetByID("SPY"); err != nil {
+			t.Errorf("GetByID: %v", err)
+		}
+		if page, err := store.ListHoldings("SPY", 10, 0); err != nil || page.Total != 1 {
+			t.Errorf("ListHoldings = %+v, %v", page, err)
+		}
+		if stored, err := store.ListDistributions("SPY", "", ""); err != nil || len(stored) != 1 {
+			t.Errorf("ListDistributions = %+v, %v", stored, err)
+		}
+
+		// Holdings of an unknown ETF fail the whole call, nothing of it is stored
+		err := store.StoreScraped(
+			[]models.ETF{{ID: "SPY", Data: []byte(`{"name":"SPY","description":"new"}`)}, {ID: "QQQ", Data: []byte(`{"name":"QQQ"}`)}},
+			[]models.FullHoldings{{Ticker: "NONE", Holdings: []models.FullHolding{{Name: "Cash"}}}},
+			[]models.ETFDistributions{{Ticker: "SPY", Distributions: []models.Distribution{{ExDate: "2024-06-21", Amount: 1.75}}}},
+		)
+		if err == nil {
+			t.Fatal("StoreScraped with holdings of an unknown ETF succeeded")
+		}
+
+		if etf, err := store.GetByID("SPY"); err != nil || !bytes.Equal(etf.Data, etfs[0].Data) {
+			t.Errorf("GetByID after the failed call = %v, %v", etf, err)
+		}
+		if _, err := store.GetByID("QQQ"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetByID of the ETF of the failed call = %v, want ErrNotFound", err)
+		}
+		if stored, err := store.ListDistributions("SPY", "", ""); err != nil || len(stored) != 1 {
+			t.Errorf("ListDistributions after the failed call = %+v, %v", stored, err)
+		}
+	})
+}
+
+func TestStoreUsers(t *testing.T) {
+	testStores(t, func(t *testing.T, store Store) {
+		user, err := store.CreateUser(models.User{Username: "analyst", Password: "hash", Role: models.RoleAnalyst})
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		if user.ID == 0 || user.CreatedAt.IsZero() {
+			t.Errorf("CreateUser did not set the generated fields: %+v", user)
+		}
+
+		if _, err := store.CreateUser(models.User{Username: "analyst", Password: "hash", Role: models.RoleViewer}); !errors.Is(err, ErrAlreadyExists) {
+			t.Errorf("CreateUser with a taken username = %v, want ErrAlreadyExists", err)
+		}
+
+		replaced, err := store.ReplaceUserPassword(user.ID, "other", "new hash")
+		if err != nil || replaced {
+			t.Errorf("ReplaceUserPassword of a changed password = %v, %v", replaced, err)
+		}
+		replaced, err = store.ReplaceUserPassword(user.ID, "hash", "new hash")
+		if err != nil || !replaced {
+			t.Errorf("ReplaceUserPassword = %v, %v", replaced, err)
+		}
+
+		if err := store.SetUserRole(user.ID, models.RoleAdmin); err != nil {
+			t.Fatalf("SetUserRole: %v", err)
+		}
+		if err := store.SetUserDisabled(user.ID, true); err != nil {
+			t.Fatalf("SetUserDisabled: %v", err)
+		}
+
+		stored, err := store.GetUserByUsername("analyst")
+		if err != nil {
+			t.Fatalf("GetUserByUsername: %v", err)
+		}
+		if stored.ID != user.ID || stored.Password != "new hash" || stored.Role != models.RoleAdmin || !stored.Disabled {
+			t.Errorf("GetUserByUsername = %+v", stored)
+		}
+
+		if err := store.DeleteUser(user.ID); err != nil {
+			t.Fatalf("DeleteUser: %v", err)
+		}
+		if _, err := store.GetUserByID(user.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetUserByID of a deleted user = %v, want ErrNotFound", err)
+		}
+		if err := store.DeleteUser(user.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("DeleteUser of a deleted user = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestStoreSessions(t *testing.T) {
+	testStores(t, func(t *testing.T, store Store) {
+		user, err := store.CreateUser(models.User{Username: "viewer", Password: "hash", Role: models.RoleViewer})
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		for _, id := range []string{"first", "second"} {
+			if err := store.CreateSession(models.Session{ID: id, UserID: user.ID, RefreshTokenHash: id + " hash"}, time.Hour); err != nil {
+				t.Fatalf("CreateSession: %v", err)
+			}
+		}
+
+		rotated, err := store.RotateRefreshToken("first", "first hash", "rotated hash", time.Hour)
+		if err != nil || !rotated {
+			t.Errorf("RotateRefreshToken = %v, %v", rotated, err)
+		}
+		rotated, err = store.RotateRefreshToken("first", "first hash", "reused hash", time.Hour)
+		if err != nil || rotated {
+			t.Errorf("RotateRefreshToken with a used refresh token = %v, %v", rotated, err)
+		}
+
+		session, err := store.GetSession("first")
+		if err != nil || session.UserID != user.ID || session.RefreshTokenHash != "rotated hash" {
+			t.Errorf("GetSession = %+v, %v", session, err)
+		}
+
+		if err := store.RevokeSession(user.ID, "second"); err != nil {
+			t.Fatalf("RevokeSession: %v", err)
+		}
+		if active, err := store.IsSessionActive("second"); err != nil || active {
+			t.Errorf("IsSessionActive of a revoked session = %v, %v", active, err)
+		}
+		if active, err := store.IsSessionActive("first"); err != nil || !active {
+			t.Errorf("IsSessionActive = %v, %v", active, err)
+		}
+
+		sessions, err := store.ListActiveSessions(user.ID)
+		if err != nil || len(sessions) != 1 || sessions[0].ID != "first" {
+			t.Errorf("ListActiveSessions = %+v, %v", sessions, err)
+		}
+
+		if err := store.RevokeUserSessions(user.ID, ""); err != nil {
+			t.Fatalf("RevokeUserSessions: %v", err)
+		}
+		if active, err := store.IsSessionActive("first"); err != nil || active {
+			t.Errorf("IsSessionActive after RevokeUserSessions = %v, %v", active, err)
+		}
+	})
+}
@@ -1,14 +1,19 @@
 package internal
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"database/sql"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/lib/pq"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/lib/pq"
 
+	"awesomeProject/migrations"
 	"awesomeProject/models"
 )
 
@@ -28,21 +33,29 @@ func NewDatabase(host, port, user, password, dbname string) (*Database, error) {
 	return &Database{db: db}, nil
 }
 
-// RunMigrations runs database migrations from the specified directory.
-func (d *Database) RunMigrations(migrationDir string) error {
-	driver, err := postgres.WithInstance(d.db, &postgres.Config{})
+// RunMigrations applies all pending migrations.
+func (d *Database) RunMigrations() error {
+	m, err := d.newMigrate()
 	if err != nil {
 		return err
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationDir),
-		"postgres", driver)
+	err = m.Up()
+	if err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	return nil
+}
+
+// RollbackMigrations reverts the given number of migrations.
+func (d *Database) RollbackMigrations(steps int) error {
+	m, err := d.newMigrate()
 	if err != nil {
 		return err
 	}
 
-	err = m.Up()
+	err = m.Steps(-steps)
 	if err != nil && err != migrate.ErrNoChange {
 		return err
 	}
@@ -50,44 +63,84 @@ func (d *Database) RunMigrations(migrationDir string) error {
 	return nil
 }
 
-// Upsert either updates an existing ETF record or creates a new one.
-func (d *Database) Upsert(etf models.ETF) error {
-	// Use a transaction to ensure atomicity
-	tx, err := d.db.Begin()
+// MigrateTo applies or reverts migrations until the database is at the given version.
+func (d *Database) MigrateTo(version uint) error {
+	m, err := d.newMigrate()
 	if err != nil {
 		return err
 	}
 
-	// Check if the ETF with the given ID exists
-	var count int
-	err = tx.QueryRow("SELECT COUNT(*) FROM etfs WHERE id = $1", etf.ID).Scan(&count)
+	err = m.Migrate(version)
+	if err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	return nil
+}
+
+// ForceMigrationVersion records the version as applied and clears the dirty
+// flag without running a migration. It is meant for repairing the database by
+// hand after a migration failed half way, -1 records that none was applied.
+func (d *Database) ForceMigrationVersion(version int) error {
+	m, err := d.newMigrate()
 	if err != nil {
-		_ = tx.Rollback()
 		return err
 	}
 
-	if count > 0 {
-		// Update the existing ETF
-		_, err = tx.Exec("UPDATE etfs SET data = $1, updated_at = NOW() WHERE id = $2", etf.Data, etf.ID)
-		if err != nil {
-			_ = tx.Rollback()
-			return err
-		}
-	} else {
-		// Insert a new ETF
-		_, err = tx.Exec("INSERT INTO etfs (id, data, created_at, updated_at) VALUES ($1, $2, NOW(), NOW())", etf.ID, etf.Data)
-		if err != nil {
-			_ = tx.Rollback()
-			return err
-		}
+	return m.Force(version)
+}
+
+// MigrationVersion returns the version of the last applied migration and
+// whether it failed half way. The version is 0 when no migration was applied.
+func (d *Database) MigrationVersion() (uint, bool, error) {
+	return readMigrationVersion(d.db, "SELECT to_regclass('schema_migrations') IS NOT NULL")
+}
+
+// LatestMigrationVersion returns the version of the newest embedded migration.
+func (d *Database) LatestMigrationVersion() (uint, error) {
+	return latestMigrationVersion(migrations.Postgres)
+}
+
+func (d *Database) newMigrate() (*migrate.Migrate, error) {
+	src, err := iofs.New(migrations.Postgres, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := postgres.WithInstance(d.db, &postgres.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithInstance("iofs", src, "postgres", driver)
+}
+
+// Upsert either updates an existing ETF record or creates a new one.
+func (d *Database) Upsert(etf models.ETF) error {
+	_, err := d.db.Exec(upsertETFsQuery(1, 0, "NOW()"), upsertETFsArgs([]models.ETF{etf})...)
+	return err
+}
+
+// UpsertMany updates or creates all ETF records in a single transaction,
+// using one multi-row statement per upsertBatchRows records.
+func (d *Database) UpsertMany(etfs []models.ETF) error {
+	etfs = dedupeETFs(etfs)
+	if len(etfs) == 0 {
+		return nil
 	}
 
-	// Commit the transaction
-	if err = tx.Commit(); err != nil {
+	tx, err := d.db.Begin()
+	if err != nil {
 		return err
 	}
 
-	return nil
+	// NOW() is the start of the transaction, every record gets the same timestamp
+	if err := upsertETFs(tx, etfs, "NOW()"); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // GetAllIDs retrieves all available ETF IDs from the database.
@@ -115,6 +168,31 @@ func (d *Database) GetAllIDs() ([]string, error) {
 	return ids, nil
 }
 
+// GetAll retrieves all ETFs ordered by ID.
+func (d *Database) GetAll() ([]models.ETF, error) {
+	rows, err := d.db.Query("SELECT id, data, created_at, updated_at FROM etfs ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var etfs []models.ETF
+
+	for rows.Next() {
+		var etf models.ETF
+		if err := rows.Scan(&etf.ID, &etf.Data, &etf.CreatedAt, &etf.UpdatedAt); err != nil {
+			return nil, err
+		}
+		etfs = append(etfs, etf)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return etfs, nil
+}
+
 // GetByID retrieves an ETF by its ID.
 func (d *Database) GetByID(id string) (*models.ETF, error) {
 	var etf models.ETF
@@ -129,7 +207,7 @@ func (d *Database) GetByID(id string) (*models.ETF, error) {
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// Handle the case where no rows match the given ID
-			return nil, fmt.Errorf("ETF not found")
+			return nil, fmt.Errorf("ETF %w", ErrNotFound)
 		}
 		// Handle other errors
 		return nil, err
@@ -138,13 +216,633 @@ func (d *Database) GetByID(id string) (*models.ETF, error) {
 	return &etf, nil
 }
 
-// UserExists checks if a user with the given username and password exists in the database.
-func (d *Database) UserExists(username, password string) (bool, error) {
-	// Query the database to check if the user exists
-	var exists bool
-	err := d.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE username = $1 AND password = $2)", username, password).Scan(&exists)
+// LastUpdatedAt returns when the most recently updated ETF was stored, nil when there is none.
+func (d *Database) LastUpdatedAt() (*time.Time, error) {
+	var updatedAt sql.NullTime
+
+	err := d.db.QueryRow("SELECT updated_at FROM etfs WHERE updated_at IS NOT NULL ORDER BY updated_at DESC LIMIT 1").Scan(&updatedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	return nullTimePtr(updatedAt), nil
+}
+
+// ReplaceHoldings stores the full holdings of every ETF in a single transaction.
+func (d *Database) ReplaceHoldings(holdings []models.FullHoldings) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := replaceHoldings(tx, holdings); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListHoldings returns a page of the full holdings of an ETF.
+func (d *Database) ListHoldings(etfID string, limit, offset int) (*models.HoldingsPage, error) {
+	return listHoldings(d.db, "to_char(as_of, 'YYYY-MM-DD')", etfID, limit, offset)
+}
+
+// UpsertDistributions stores the distributions of every ETF in a single transaction.
+func (d *Database) UpsertDistributions(distributions []models.ETFDistributions) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := upsertDistributions(tx, distributions); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListDistributions returns the distributions of an ETF within a date range.
+func (d *Database) ListDistributions(etfID, from, to string) ([]models.Distribution, error) {
+	return listDistributions(d.db, "to_char(%s, 'YYYY-MM-DD')", etfID, from, to)
+}
+
+// StoreScraped stores the ETF records, their full holdings and their distributions in a single transaction.
+func (d *Database) StoreScraped(etfs []models.ETF, holdings []models.FullHoldings, distributions []models.ETFDistributions) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = upsertETFs(tx, dedupeETFs(etfs), "NOW()")
+	if err == nil {
+		err = replaceHoldings(tx, holdings)
+	}
+	if err == nil {
+		err = upsertDistributions(tx, distributions)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Ping checks that the database can be reached.
+func (d *Database) Ping() error {
+	return d.db.Ping()
+}
+
+// userColumns lists the users columns in the order expected by scanUser.
+const userColumns = "id, username, password, COALESCE(email, ''), role, disabled, last_login_at, created_at, COALESCE(updated_at, created_at)"
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	var user models.User
+	var lastLogin sql.NullTime
+
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.Password,
+		&user.Email,
+		&user.Role,
+		&user.Disabled,
+		&lastLogin,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastLogin.Valid {
+		user.LastLoginAt = &lastLogin.Time
+	}
+
+	return &user, nil
+}
+
+// CreateUser inserts a new user and returns it with the generated fields set.
+func (d *Database) CreateUser(user models.User) (*models.User, error) {
+	row := d.db.QueryRow(
+		"INSERT INTO users (username, password, email, role, disabled, created_at, updated_at) "+
+			"VALUES ($1, $2, NULLIF($3, ''), $4, $5, NOW(), NOW()) RETURNING "+userColumns,
+		user.Username, user.Password, user.Email, user.Role, user.Disabled,
+	)
+
+	created, err := scanUser(row)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("user %w", ErrAlreadyExists)
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ListUsers retrieves all users ordered by ID.
+func (d *Database) ListUsers() ([]models.User, error) {
+	rows, err := d.db.Query("SELECT " + userColumns + " FROM users ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetUserByID retrieves a user by ID.
+func (d *Database) GetUserByID(id int) (*models.User, error) {
+	user, err := scanUser(d.db.QueryRow("SELECT "+userColumns+" FROM users WHERE id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetUserByUsername retrieves a user by username.
+func (d *Database) GetUserByUsername(username string) (*models.User, error) {
+	user, err := scanUser(d.db.QueryRow("SELECT "+userColumns+" FROM users WHERE username = $1", username))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateUserPassword replaces the stored password hash of a user.
+func (d *Database) UpdateUserPassword(id int, password string) error {
+	res, err := d.db.Exec("UPDATE users SET password = $1, updated_at = NOW() WHERE id = $2", password, id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res, "user")
+}
+
+// ReplaceUserPassword replaces the stored password hash of a user while it still is current.
+func (d *Database) ReplaceUserPassword(id int, current, password string) (bool, error) {
+	res, err := d.db.Exec("UPDATE users SET password = $1, updated_at = NOW() WHERE id = $2 AND password = $3", password, id, current)
 	if err != nil {
 		return false, err
 	}
-	return exists, nil
+
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+// SetUserDisabled enables or disables a user.
+func (d *Database) SetUserDisabled(id int, disabled bool) error {
+	res, err := d.db.Exec("UPDATE users SET disabled = $1, updated_at = NOW() WHERE id = $2", disabled, id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res, "user")
+}
+
+// SetUserRole changes the role of a user.
+func (d *Database) SetUserRole(id int, role models.Role) error {
+	res, err := d.db.Exec("UPDATE users SET role = $1, updated_at = NOW() WHERE id = $2", role, id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res, "user")
+}
+
+// UpdateLastLogin sets the last login time of a user to now.
+func (d *Database) UpdateLastLogin(id int) error {
+	_, err := d.db.Exec("UPDATE users SET last_login_at = NOW() WHERE id = $1", id)
+	return err
+}
+
+// DeleteUser removes a user.
+func (d *Database) DeleteUser(id int) error {
+	res, err := d.db.Exec("DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res, "user")
+}
+
+// sessionColumns lists the sessions columns in the order expected by scanSession.
+const sessionColumns = "id, user_id, refresh_token_hash, COALESCE(user_agent, ''), COALESCE(ip, ''), created_at, last_used_at, expires_at, revoked_at"
+
+func scanSession(row rowScanner) (*models.Session, error) {
+	var session models.Session
+	var revokedAt sql.NullTime
+
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.RefreshTokenHash,
+		&session.UserAgent,
+		&session.IP,
+		&session.CreatedAt,
+		&session.LastUsedAt,
+		&session.ExpiresAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+
+	return &session, nil
+}
+
+// CreateSession stores a new session that expires after the given TTL.
+func (d *Database) CreateSession(session models.Session, ttl time.Duration) error {
+	_, err := d.db.Exec(
+		"INSERT INTO sessions (id, user_id, refresh_token_hash, user_agent, ip, created_at, last_used_at, expires_at) "+
+			"VALUES ($1, $2, $3, $4, $5, NOW(), NOW(), NOW() + make_interval(secs => $6))",
+		session.ID, session.UserID, session.RefreshTokenHash, session.UserAgent, session.IP, int64(ttl.Seconds()),
+	)
+	return err
+}
+
+// RotateRefreshToken replaces the refresh token hash of an active session and extends it by the TTL.
+// It returns false when the session is unknown, revoked, expired or the old hash does not match.
+func (d *Database) RotateRefreshToken(id, oldHash, newHash string, ttl time.Duration) (bool, error) {
+	res, err := d.db.Exec(
+		"UPDATE sessions SET refresh_token_hash = $1, last_used_at = NOW(), expires_at = NOW() + make_interval(secs => $2) "+
+			"WHERE id = $3 AND refresh_token_hash = $4 AND revoked_at IS NULL AND expires_at > NOW()",
+		newHash, int64(ttl.Seconds()), id, oldHash,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// GetSession retrieves a session by ID.
+func (d *Database) GetSession(id string) (*models.Session, error) {
+	session, err := scanSession(d.db.QueryRow("SELECT "+sessionColumns+" FROM sessions WHERE id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("session %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// ListActiveSessions retrieves the sessions of a user that are neither revoked nor expired.
+func (d *Database) ListActiveSessions(userID int) ([]models.Session, error) {
+	rows, err := d.db.Query(
+		"SELECT "+sessionColumns+" FROM sessions "+
+			"WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW() ORDER BY last_used_at DESC",
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// IsSessionActive reports whether the session is neither revoked nor expired and its user is enabled.
+func (d *Database) IsSessionActive(id string) (bool, error) {
+	var active bool
+	err := d.db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM sessions s JOIN users u ON u.id = s.user_id "+
+			"WHERE s.id = $1 AND s.revoked_at IS NULL AND s.expires_at > NOW() AND NOT u.disabled)",
+		id,
+	).Scan(&active)
+	if err != nil {
+		return false, err
+	}
+	return active, nil
+}
+
+// RevokeSession revokes a single session of a user.
+func (d *Database) RevokeSession(userID int, id string) error {
+	res, err := d.db.Exec(
+		"UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL",
+		id, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res, "session")
+}
+
+// RevokeUserSessions revokes every session of a user except the one with the given ID.
+// Pass an empty ID to revoke all of them.
+func (d *Database) RevokeUserSessions(userID int, exceptID string) error {
+	_, err := d.db.Exec(
+		"UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL",
+		userID, exceptID,
+	)
+	return err
+}
+
+// apiKeyColumns lists the api_keys columns in the order expected by scanAPIKey.
+const apiKeyColumns = "id, user_id, name, prefix, key_hash, scope, created_at, expires_at, last_used_at, revoked_at"
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	var key models.APIKey
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Scope,
+		&key.CreatedAt,
+		&expiresAt,
+		&lastUsedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.ExpiresAt = nullTimePtr(expiresAt)
+	key.LastUsedAt = nullTimePtr(lastUsedAt)
+	key.RevokedAt = nullTimePtr(revokedAt)
+
+	return &key, nil
+}
+
+// CreateAPIKey stores a new API key. A zero TTL creates a key that never expires.
+func (d *Database) CreateAPIKey(key models.APIKey, ttl time.Duration) (*models.APIKey, error) {
+	row := d.db.QueryRow(
+		"INSERT INTO api_keys (user_id, name, prefix, key_hash, scope, created_at, expires_at) "+
+			"VALUES ($1, $2, $3, $4, $5, NOW(), NOW() + make_interval(secs => NULLIF($6::double precision, 0))) "+
+			"RETURNING "+apiKeyColumns,
+		key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scope, ttl.Seconds(),
+	)
+
+	return scanAPIKey(row)
+}
+
+// ListAPIKeys retrieves all API keys of a user, including revoked and expired ones.
+func (d *Database) ListAPIKeys(userID int) ([]models.APIKey, error) {
+	rows, err := d.db.Query("SELECT "+apiKeyColumns+" FROM api_keys WHERE user_id = $1 ORDER BY id", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// GetActiveAPIKey retrieves an API key by its prefix if it is neither revoked nor expired.
+func (d *Database) GetActiveAPIKey(prefix string) (*models.APIKey, error) {
+	key, err := scanAPIKey(d.db.QueryRow(
+		"SELECT "+apiKeyColumns+" FROM api_keys "+
+			"WHERE prefix = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())",
+		prefix,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("api key %w", ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// TouchAPIKey records that an API key has just been used.
+func (d *Database) TouchAPIKey(id int) error {
+	_, err := d.db.Exec("UPDATE api_keys SET last_used_at = NOW() WHERE id = $1", id)
+	return err
+}
+
+// RevokeAPIKey revokes an API key of a user.
+func (d *Database) RevokeAPIKey(userID, id int) error {
+	res, err := d.db.Exec(
+		"UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL",
+		id, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(res, "api key")
+}
+
+// IncrementQuota counts a request of the client for the day (YYYY-MM-DD) and returns the new count.
+func (d *Database) IncrementQuota(clientKey string, day string) (int, error) {
+	var count int
+	err := d.db.QueryRow(
+		"INSERT INTO rate_quotas (client_key, day, requests) VALUES ($1, $2, 1) "+
+			"ON CONFLICT (client_key, day) DO UPDATE SET requests = rate_quotas.requests + 1 RETURNING requests",
+		clientKey, day,
+	).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// DeleteQuotasBefore removes the quota counters of days before the given day (YYYY-MM-DD).
+func (d *Database) DeleteQuotasBefore(day string) error {
+	_, err := d.db.Exec("DELETE FROM rate_quotas WHERE day < $1", day)
+	return err
+}
+
+// InsertAuditEvent stores an audit event.
+func (d *Database) InsertAuditEvent(event models.AuditEvent) error {
+	// JSONB has to be sent as text, lib/pq would encode a byte slice as bytea
+	var details sql.NullString
+	if len(event.Details) > 0 {
+		b, err := json.Marshal(event.Details)
+		if err != nil {
+			return err
+		}
+		details = sql.NullString{String: string(b), Valid: true}
+	}
+
+	_, err := d.db.Exec(
+		"INSERT INTO audit_events (occurred_at, event_type, username, ip, request_id, method, path, status, resource, details) "+
+			"VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, 0), NULLIF($9, ''), $10)",
+		event.OccurredAt.UTC(), event.Type, event.Username, event.IP, event.RequestID,
+		event.Method, event.Path, event.Status, event.Resource, details,
+	)
+	return err
+}
+
+// ListAuditEvents retrieves audit events matching the filter, newest first.
+func (d *Database) ListAuditEvents(filter models.AuditFilter) ([]models.AuditEvent, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Username != "" {
+		addCondition("username = $%d", filter.Username)
+	}
+	if filter.Type != "" {
+		addCondition("event_type = $%d", filter.Type)
+	}
+	if filter.Resource != "" {
+		addCondition("resource = $%d", filter.Resource)
+	}
+	if filter.RequestID != "" {
+		addCondition("request_id = $%d", filter.RequestID)
+	}
+	if filter.From != nil {
+		addCondition("occurred_at >= $%d", filter.From.UTC())
+	}
+	if filter.To != nil {
+		addCondition("occurred_at < $%d", filter.To.UTC())
+	}
+
+	query := "SELECT id, occurred_at, event_type, COALESCE(username, ''), COALESCE(ip, ''), COALESCE(request_id, ''), " +
+		"COALESCE(method, ''), COALESCE(path, ''), COALESCE(status, 0), COALESCE(resource, ''), details FROM audit_events"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY occurred_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.AuditEvent{}
+
+	for rows.Next() {
+		var event models.AuditEvent
+		var details []byte
+
+		err := rows.Scan(
+			&event.ID,
+			&event.OccurredAt,
+			&event.Type,
+			&event.Username,
+			&event.IP,
+			&event.RequestID,
+			&event.Method,
+			&event.Path,
+			&event.Status,
+			&event.Resource,
+			&details,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &event.Details); err != nil {
+				return nil, err
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// expectAffected returns ErrNotFound when the statement did not change any row.
+func expectAffected(res sql.Result, entity string) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s %w", entity, ErrNotFound)
+	}
+
+	return nil
+}
+
+// isUniqueViolation reports whether the error is a PostgreSQL unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
 }
@@ -0,0 +1,182 @@
+package internal
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ThrottleLimit configures how failed logins of one username or IP are slowed down.
+type ThrottleLimit struct {
+	// FreeAttempts is the number of failures allowed without any delay.
+	FreeAttempts int
+	// BaseDelay is the delay after the first failure past FreeAttempts, it doubles with every further failure.
+	BaseDelay time.Duration
+	// MaxDelay caps the progressive delay.
+	MaxDelay time.Duration
+	// LockoutAfter is the number of failures that locks the username or IP out.
+	LockoutAfter int
+	// LockoutDuration is how long a lockout lasts.
+	LockoutDuration time.Duration
+	// ResetAfter forgets the failures when there was none for this long.
+	ResetAfter time.Duration
+}
+
+// LoginThrottleConfig holds the limits per username and per client IP.
+// Many users can share an IP, so its limits should be more generous.
+type LoginThrottleConfig struct {
+	Username ThrottleLimit
+	IP       ThrottleLimit
+}
+
+// DefaultLoginThrottleConfig returns the limits used when nothing else is configured.
+func DefaultLoginThrottleConfig() LoginThrottleConfig {
+	return LoginThrottleConfig{
+		Username: ThrottleLimit{
+			FreeAttempts:    3,
+			BaseDelay:       time.Second,
+			MaxDelay:        time.Minute,
+			LockoutAfter:    10,
+			LockoutDuration: 15 * time.Minute,
+			ResetAfter:      time.Hour,
+		},
+		IP: ThrottleLimit{
+			FreeAttempts:    10,
+			BaseDelay:       time.Second,
+			MaxDelay:        time.Minute,
+			LockoutAfter:    50,
+			LockoutDuration: 15 * time.Minute,
+			ResetAfter:      time.Hour,
+		},
+	}
+}
+
+// LoginThrottle tracks failed logins per username and per IP and tells when
+// the next attempt is allowed.
+type LoginThrottle struct {
+	config LoginThrottleConfig
+	logger *logrus.Logger
+
+	mu        sync.Mutex
+	failures  map[string]*loginFailures
+	lastSweep time.Time
+}
+
+type loginFailures struct {
+	count        int
+	last         time.Time
+	blockedUntil time.Time
+	limit        ThrottleLimit
+}
+
+func NewLoginThrottle(config LoginThrottleConfig, logger *logrus.Logger) *LoginThrottle {
+	return &LoginThrottle{
+		config:   config,
+		logger:   logger,
+		failures: map[string]*loginFailures{},
+	}
+}
+
+// Wait returns how long the client has to wait before the next login attempt
+// for the username from the IP is allowed. Zero means it is allowed now.
+func (t *LoginThrottle) Wait(username, ip string) time.Duration {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var wait time.Duration
+	for _, key := range []string{usernameThrottleKey(username), ipThrottleKey(ip)} {
+		f, ok := t.failures[key]
+		if !ok {
+			continue
+		}
+
+		if d := f.blockedUntil.Sub(now); d > wait {
+			wait = d
+		}
+	}
+
+	return wait
+}
+
+// Failure records a failed login for the username from the IP. It reports
+// whether this failure locked the username or the IP out.
+func (t *LoginThrottle) Failure(username, ip string) bool {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.lastSweep) > time.Minute {
+		t.sweep(now)
+	}
+
+	userLocked := t.record(usernameThrottleKey(username), t.config.Username, now, username, ip)
+	ipLocked := t.record(ipThrottleKey(ip), t.config.IP, now, username, ip)
+
+	return userLocked || ipLocked
+}
+
+// Success forgets the failed logins of the username. The failures of the IP
+// are kept, otherwise one valid account would be enough to reset them.
+func (t *LoginThrottle) Success(username string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.failures, usernameThrottleKey(username))
+}
+
+func (t *LoginThrottle) record(key string, limit ThrottleLimit, now time.Time, username, ip string) bool {
+	f, ok := t.failures[key]
+	if !ok || now.Sub(f.last) > limit.ResetAfter {
+		f = &loginFailures{limit: limit}
+		t.failures[key] = f
+	}
+
+	f.count++
+	f.last = now
+
+	switch {
+	case limit.LockoutAfter > 0 && f.count >= limit.LockoutAfter:
+		f.blockedUntil = now.Add(limit.LockoutDuration)
+		if f.count == limit.LockoutAfter {
+			t.logger.WithFields(logrus.Fields{
+				"key":      key,
+				"username": username,
+				"ip":       ip,
+				"failures": f.count,
+				"until":    f.blockedUntil,
+			}).Warn("Login locked out after too many failed attempts")
+			return true
+		}
+	case f.count > limit.FreeAttempts:
+		delay := limit.BaseDelay << uint(f.count-limit.FreeAttempts-1)
+		if delay <= 0 || delay > limit.MaxDelay {
+			delay = limit.MaxDelay
+		}
+		f.blockedUntil = now.Add(delay)
+	}
+
+	return false
+}
+
+// sweep drops failures that are old enough to be forgotten.
+func (t *LoginThrottle) sweep(now time.Time) {
+	for key, f := range t.failures {
+		if now.Sub(f.last) > f.limit.ResetAfter && now.After(f.blockedUntil) {
+			delete(t.failures, key)
+		}
+	}
+	t.lastSweep = now
+}
+
+func usernameThrottleKey(username string) string {
+	return "user:" + strings.ToLower(username)
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// randomID returns a random hex string built from n random bytes.
+func randomID(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// randomSecret returns a random URL safe string built from n random bytes.
+func randomSecret(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken hashes high entropy secrets such as refresh tokens before they are stored.
+// Unlike passwords they cannot be brute forced, so a fast unsalted hash is enough.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newRefreshToken returns a refresh token for the session and the secret part of it.
+func newRefreshToken(sessionID string) (token string, secret string, err error) {
+	secret, err = randomSecret(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	return sessionID + "." + secret, secret, nil
+}
+
+// parseRefreshToken splits a refresh token into the session ID and the secret.
+func parseRefreshToken(token string) (sessionID string, secret string, ok bool) {
+	sessionID, secret, ok = strings.Cut(token, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return "", "", false
+	}
+
+	return sessionID, secret, true
+}
+
+// apiKeyPrefix marks API keys so they are easy to recognize, e.g. by secret scanners.
+const apiKeyPrefix = "etf"
+
+// newAPIKey returns a new API key and its lookup prefix.
+// The key has the form etf_<prefix>_<secret>.
+func newAPIKey() (key string, prefix string, err error) {
+	prefix, err = randomID(8)
+	if err != nil {
+		return "", "", err
+	}
+
+	secret, err := randomSecret(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	return apiKeyPrefix + "_" + prefix + "_" + secret, prefix, nil
+}
+
+// parseAPIKey returns the lookup prefix of an API key.
+func parseAPIKey(key string) (prefix string, ok bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+
+	return parts[1], true
+}
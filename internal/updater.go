@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
@@ -18,10 +19,14 @@ import (
 	"awesomeProject/models"
 )
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+)
 
 const (
-	etfFinerWithFilter = "/us/en/individual/etfs/fund-finder?g=assetclass%3Aequity&tab=overview"
+	// the fund finder lists the funds of every asset class when no filter is set
+	etfFinder = "/us/en/individual/etfs/fund-finder?tab=overview"
 
 	// common selectors
 	tableSelector     = "table.data-table"
@@ -34,32 +39,73 @@ const (
 	// etf description selectors
 	descriptionSelector = "section.comp-text:has(h2.comp-title:contains('About this Benchmark')) div.ssmp-richtext"
 
+	// etf key facts selectors, the facts are the label and data cells of the
+	// sections titled with one of keyFactSectionTitles
+	keyFactSectionSelector = "div[data-fundComponent='true']:has(h3:contains('%s'))"
+	keyFactRowSelector     = "table.data-table tr"
+
 	// etf top holdings selectors
 	topHoldingsSectionSelector = "section:has(h3:contains('Top Holdings'))"
 
-	// etf sector selectors
+	// etf sector selectors, 'Sector Breakdown' would also match the fund sector breakdown
 	sectorFundBreakdownDivSelector = "div[data-fundComponent='true']:has(h3:contains('Fund Sector Breakdown'))"
-	sectorBreakdownDivSelector     = "div[data-fundComponent='true']:has(h3:contains('Sector Breakdown'))"
+	sectorBreakdownDivSelector     = "div[data-fundComponent='true']:has(h3:contains('Sector Breakdown')):not(:has(h3:contains('Fund Sector Breakdown')))"
 	sectorIndustryDivSelector      = "div[data-fundComponent='true']:has(h3:contains('Fund Industry Allocation'))"
 	sectorSubIndustryDivSelector   = "div[data-fundComponent='true']:has(h3:contains('Fund Sub-Industry Allocation'))"
 
+	// etf fixed income selectors
+	creditQualityDivSelector  = "div[data-fundComponent='true']:has(h3:contains('Credit Quality'))"
+	maturityLadderDivSelector = "div[data-fundComponent='true']:has(h3:contains('Maturity Ladder')), div[data-fundComponent='true']:has(h3:contains('Maturity Breakdown'))"
+
 	// etf geographical selectors
 	geographicalSelector = "input#fund-geographical-breakdown"
 
+	// etf distribution selectors, the distribution table is the data table with an ex-date column
+	distributionTableSelector = "table"
+
 	semaphoreCapacity = 50
 )
 
+// UpdaterConfig controls how the updater writes the scraped ETFs.
+type UpdaterConfig struct {
+	// BatchSize is the number of ETFs stored per UpsertMany call.
+	BatchSize int
+	// Atomic stores the whole run in a single transaction once every fund page
+	// was scraped, including the full holdings and distributions, so a failed
+	// run leaves the previous data untouched.
+	Atomic bool
+}
+
+// DefaultUpdaterConfig returns the settings used when nothing else is configured.
+func DefaultUpdaterConfig() UpdaterConfig {
+	return UpdaterConfig{
+		BatchSize: 100,
+	}
+}
+
 type DailyDataUpdater struct {
-	host   string
-	logger *logrus.Logger
-	store  *Database
+	host    string
+	logger  *logrus.Logger
+	store   ETFStore
+	updates *UpdateBroker
+	config  UpdaterConfig
+
+	// lastRun is the outcome of the last completed UpdateData call
+	mu      sync.Mutex
+	lastRun *models.UpdaterRun
 }
 
-func NewDailyDataUpdater(host string, db *Database, log *logrus.Logger) *DailyDataUpdater {
+func NewDailyDataUpdater(host string, db ETFStore, updates *UpdateBroker, config UpdaterConfig, log *logrus.Logger) *DailyDataUpdater {
+	if config.BatchSize < 1 {
+		config.BatchSize = DefaultUpdaterConfig().BatchSize
+	}
+
 	return &DailyDataUpdater{
-		host:   host,
-		logger: log,
-		store:  db,
+		host:    host,
+		logger:  log,
+		store:   db,
+		updates: updates,
+		config:  config,
 	}
 }
 
@@ -99,11 +145,52 @@ func (u *DailyDataUpdater) Run() {
 	}
 }
 
+// UpdateData scrapes every ETF and stores the data, the outcome is available from LastRun.
 func (u *DailyDataUpdater) UpdateData() error {
+	run := models.UpdaterRun{StartedAt: time.Now()}
+
+	result := u.update()
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Stored = result.stored
+	run.Failed = result.failed
+	run.Succeeded = result.err == nil
+	if result.err != nil {
+		run.Error = result.err.Error()
+	}
+
+	u.mu.Lock()
+	u.lastRun = &run
+	u.mu.Unlock()
+
+	return result.err
+}
+
+// LastRun returns the outcome of the last completed run, nil before the first one finished.
+func (u *DailyDataUpdater) LastRun() *models.UpdaterRun {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.lastRun == nil {
+		return nil
+	}
+	run := *u.lastRun
+	return &run
+}
+
+// updateResult counts the ETFs of a run.
+type updateResult struct {
+	stored int
+	failed int
+	err    error
+}
+
+func (u *DailyDataUpdater) update() updateResult {
 	// Get data paths for updating
 	fetchPaths, err := u.getPaths()
 	if err != nil {
-		return fmt.Errorf("failed to get data paths: %v", err)
+		return updateResult{err: fmt.Errorf("failed to get data paths: %v", err)}
 	}
 
 	// Use WaitGroup to wait for completion of all updates
@@ -112,6 +199,16 @@ func (u *DailyDataUpdater) UpdateData() error {
 	// Define a channel-based semaphore with a capacity
 	semaphore := make(chan struct{}, semaphoreCapacity)
 
+	// The parsed ETFs are stored in batches by a single writer
+	parsed := make(chan scrapedETF, semaphoreCapacity)
+	stored := make(chan updateResult, 1)
+
+	go func() {
+		stored <- u.storeETFs(parsed)
+	}()
+
+	var fetchFailed int32
+
 	// Launch data updates for each path in parallel
 	for path := range fetchPaths {
 		wg.Add(1)
@@ -126,157 +223,513 @@ func (u *DailyDataUpdater) UpdateData() error {
 				wg.Done()
 			}()
 
-			u.updateETF(path)
+			if etf, ok := u.fetchPath(path); ok {
+				parsed <- etf
+			} else {
+				atomic.AddInt32(&fetchFailed, 1)
+			}
 		}(path)
 	}
 
 	// Wait for completion of all updates
 	wg.Wait()
+	close(parsed)
 
-	return nil
+	result := <-stored
+	result.failed += int(fetchFailed)
+
+	return result
 }
 
-func (u *DailyDataUpdater) updateETF(path string) {
+// scrapedETF is the data scraped for a fund, holdings is nil when the fund
+// page links no holdings file or it could not be parsed.
+type scrapedETF struct {
+	data          models.ETFData
+	holdings      *models.FullHoldings
+	distributions []models.Distribution
+}
+
+// fetchPath scrapes the fund page at path and its holdings file. Failures are
+// logged, a fund whose page could not be parsed reports false.
+func (u *DailyDataUpdater) fetchPath(path string) (scrapedETF, bool) {
 	// Create the URL by combining the host and path
 	url := u.host + path
 
-	// Send an HTTP GET request to the URL
-	resp, err := http.Get(url)
+	// Download and parse the fund page
+	doc, err := fetchDocument(url)
 	if err != nil {
-		u.logger.Error(err)
-		return
+		u.logger.Errorf("Could not build ETF, error: %s, URL: %s", err, url)
+		return scrapedETF{}, false
 	}
-	defer resp.Body.Close()
 
-	// Read the HTML content from the response body
-	htmlContent, err := io.ReadAll(resp.Body)
+	etfData, err := u.buildETF(doc)
 	if err != nil {
-		u.logger.Error(err)
+		u.logger.Errorf("Could not build ETF, error: %s, URL: %s", err, url)
+		return scrapedETF{}, false
+	}
+
+	etf := scrapedETF{
+		data:          *etfData,
+		distributions: u.findDistributions(doc),
+	}
+
+	// The top holdings are kept when the holdings file is missing
+	if holdingsURL := u.findHoldingsFileURL(doc); holdingsURL != "" {
+		etf.holdings, err = u.FetchHoldings(holdingsURL, etfData.Name)
+		if err != nil {
+			u.logger.Errorf("Could not parse the holdings file, error: %s, URL: %s", err, holdingsURL)
+		}
+	}
+
+	return etf, true
+}
+
+// storeETFs stores the ETFs received from parsed until it is closed. A run
+// that is not atomic stores them in batches and keeps the batches stored
+// before one fails, the run still counts as failed.
+func (u *DailyDataUpdater) storeETFs(parsed <-chan scrapedETF) updateResult {
+	var result updateResult
+	var batch []scrapedETF
+
+	flush := func() {
+		if err := u.flush(batch); err != nil {
+			result.failed += len(batch)
+			if u.config.Atomic {
+				result.err = fmt.Errorf("failed to store the ETFs, the previous data was kept: %v", err)
+			} else {
+				result.err = fmt.Errorf("failed to store %d ETFs: %v", result.failed, err)
+			}
+		} else {
+			result.stored += len(batch)
+		}
+		batch = nil
+	}
+
+	for etf := range parsed {
+		batch = append(batch, etf)
+
+		if !u.config.Atomic && len(batch) >= u.config.BatchSize {
+			flush()
+		}
+	}
+
+	if len(batch) > 0 {
+		flush()
+	}
+
+	return result
+}
+
+// flush stores the batch and publishes the stored ETFs. An atomic run stores
+// the ETF data, the full holdings and the distributions in one transaction.
+func (u *DailyDataUpdater) flush(batch []scrapedETF) error {
+	etfs := make([]models.ETF, len(batch))
+	var holdings []models.FullHoldings
+	var distributions []models.ETFDistributions
+	for i, etf := range batch {
+		// Set the ETF's ID as its name and serialize the ETFData to JSON
+		etfs[i] = models.ETF{
+			ID:   etf.data.Name,
+			Data: etf.data.ToJson(),
+		}
+
+		if etf.holdings != nil {
+			holdings = append(holdings, *etf.holdings)
+		}
+		if len(etf.distributions) > 0 {
+			distributions = append(distributions, models.ETFDistributions{Ticker: etf.data.Name, Distributions: etf.distributions})
+		}
+	}
+
+	store := u.storeBatch
+	if u.config.Atomic {
+		store = u.store.StoreScraped
+	}
+
+	if err := store(etfs, holdings, distributions); err != nil {
+		u.logger.Errorf("Could not store %d ETFs, error: %s", len(etfs), err)
+		return err
+	}
+
+	for _, etf := range batch {
+		u.publish(etf.data)
+	}
+
+	return nil
+}
+
+// storeBatch upserts the ETF data in a single transaction. The full holdings
+// and the distributions are stored afterwards in transactions of their own,
+// failing to store them is only logged and keeps the ETF data.
+func (u *DailyDataUpdater) storeBatch(etfs []models.ETF, holdings []models.FullHoldings, distributions []models.ETFDistributions) error {
+	// Upsert the ETF data into the database
+	if err := u.store.UpsertMany(etfs); err != nil {
+		return err
+	}
+
+	if len(holdings) > 0 {
+		if err := u.store.ReplaceHoldings(holdings); err != nil {
+			u.logger.Errorf("Could not store the holdings of %d ETFs, error: %s", len(holdings), err)
+		}
+	}
+
+	if len(distributions) > 0 {
+		if err := u.store.UpsertDistributions(distributions); err != nil {
+			u.logger.Errorf("Could not store the distributions of %d ETFs, error: %s", len(distributions), err)
+		}
+	}
+
+	return nil
+}
+
+// publish tells the subscribers of the update broker about the stored ETF.
+func (u *DailyDataUpdater) publish(etf models.ETFData) {
+	if u.updates == nil {
 		return
 	}
 
-	// Create a string reader from the HTML content
-	reader := strings.NewReader(string(htmlContent))
+	u.updates.Publish(models.ETFUpdate{ETF: etf, UpdatedAt: time.Now()})
+}
 
-	// Parse the HTML document using goquery
-	doc, err := goquery.NewDocumentFromReader(reader)
+// FetchETF downloads the fund page at url and parses it without storing the result.
+func (u *DailyDataUpdater) FetchETF(url string) (*models.ETFData, error) {
+	doc, err := fetchDocument(url)
 	if err != nil {
-		u.logger.Error(err)
-		return
+		return nil, err
 	}
 
 	// Build an ETF object from the parsed HTML document
-	etf, err := u.buildETF(doc)
+	return u.buildETF(doc)
+}
+
+// fetchDocument downloads and parses the HTML page at url.
+func fetchDocument(url string) (*goquery.Document, error) {
+	// Send an HTTP GET request to the URL
+	resp, err := http.Get(url)
 	if err != nil {
-		u.logger.Errorf("Could not build ETF, error: %s, URL: %s", err, url)
-		return
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	// Upsert the ETF data into the database
-	err = u.store.Upsert(etf)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return goquery.NewDocumentFromReader(resp.Body)
+}
+
+// ParseETF parses the HTML of a fund page.
+func (u *DailyDataUpdater) ParseETF(r io.Reader) (*models.ETFData, error) {
+	// Parse the HTML document using goquery
+	doc, err := goquery.NewDocumentFromReader(r)
 	if err != nil {
-		u.logger.Errorf("Could not upsert ETF, error: %s, URL: %s", err, url)
+		return nil, err
 	}
+
+	// Build an ETF object from the parsed HTML document
+	return u.buildETF(doc)
 }
 
-func (u *DailyDataUpdater) buildETF(doc *goquery.Document) (etf models.ETF, err error) {
-	var etfData models.ETFData
+func (u *DailyDataUpdater) buildETF(doc *goquery.Document) (*models.ETFData, error) {
+	var (
+		etfData models.ETFData
+		err     error
+	)
 
 	// Extract and clean the ETF name from the HTML document
 	etfData.Name = strings.TrimSpace(doc.Find(tickerSelector).Text())
 	if etfData.Name == "" {
-		return etf, errors.New("not found name")
+		return nil, errors.New("not found name")
 	}
 
 	// Extract the ETF description from the HTML document
 	etfData.Description = doc.Find(descriptionSelector).Text()
 	if etfData.Description == "" {
-		return etf, errors.New("description found name")
+		return nil, errors.New("description found name")
 	}
 
-	// Find and populate the ETF's top holdings
-	etfData.TopHoldings, err = u.findHoldings(doc)
-	if err != nil {
-		return etf, fmt.Errorf("findHoldings returns: %s", err)
+	// Extract the key facts such as NAV and expense ratio, older pages may lack some of them
+	facts := u.findKeyFacts(doc)
+	etfData.Characteristics = u.findCharacteristics(facts)
+
+	// Extract the bond characteristics, only fixed income and multi-asset funds show them
+	etfData.FixedIncome = u.findFixedIncome(doc, facts)
+	etfData.AssetClass = findAssetClass(facts, etfData.FixedIncome)
+
+	// Equity funds always show top holdings and sectors, bond and multi-asset funds may not
+	optional := func(err error) bool {
+		return err == ErrNotFound && etfData.AssetClass != models.AssetClassEquity
 	}
 
-	// Find and populate the ETF's sectors data
-	etfData.Sectors, err = u.findSectors(doc)
-	if err != nil {
-		return etf, fmt.Errorf("findSectors returns: %s", err)
+	// Find and populate the ETF's top holdings and those of its benchmark index
+	etfData.TopHoldings, etfData.IndexTopHoldings, err = u.findHoldings(doc)
+	if err != nil && !optional(err) {
+		return nil, fmt.Errorf("findHoldings returns: %s", err)
+	}
+
+	// Find and populate the ETF's sector, industry and sub-industry breakdowns
+	etfData.Breakdowns, err = u.findBreakdowns(doc)
+	if err != nil && !optional(err) {
+		return nil, fmt.Errorf("findBreakdowns returns: %s", err)
 	}
+	etfData.Sectors = sectorWeights(etfData.Breakdowns)
 
 	// Find and populate the ETF's countries data
 	etfData.Countries, err = u.findCountries(doc)
 	if err != nil && err != ErrNotFound { // It's okay if ETF doesn't have geoData
-		return etf, fmt.Errorf("findCountries returns: %s", err)
+		return nil, fmt.Errorf("findCountries returns: %s", err)
 	}
 
-	// Set the ETF's ID as its name and serialize the ETFData to JSON
-	etf.ID = etfData.Name
-	etf.Data = etfData.ToJson()
+	return &etfData, nil
+}
+
+// keyFactLabels lists the labels each key fact is shown with, normalized by normalizeLabel.
+var keyFactLabels = map[string][]string{
+	"nav":                 {"nav", "nav per share", "net asset value"},
+	"market_price":        {"market price", "closing price"},
+	"gross_expense_ratio": {"gross expense ratio"},
+	"aum":                 {"assets under management", "aum", "total net assets"},
+	"number_of_holdings":  {"number of holdings"},
+	"inception_date":      {"inception date", "fund inception date"},
+	"benchmark":           {"benchmark", "primary benchmark"},
+	"primary_exchange":    {"primary exchange", "exchange", "listing exchange"},
+	"asset_class":         {"asset class"},
+	"duration":            {"effective duration", "option adjusted duration", "modified duration", "duration"},
+	"yield_to_maturity":   {"yield to maturity", "average yield to maturity", "weighted average yield to maturity"},
+	"yield_to_worst":      {"yield to worst", "average yield to worst"},
+}
+
+// keyFactSectionTitles are the headings of the sections showing key facts.
+// Other data tables such as the top holdings use labels like "Exchange" too,
+// so only these sections are read.
+var keyFactSectionTitles = []string{
+	"Key Features",
+	"Fund Information",
+	"Fund Characteristics",
+	"Fund Net Asset Value",
+	"Fund Market Price",
+}
 
-	return etf, nil
+// keyFacts maps the normalized labels of the key fact sections to the first value shown for them.
+type keyFacts map[string]string
+
+// findKeyFacts collects the label and data cells of the key fact sections.
+func (u *DailyDataUpdater) findKeyFacts(doc *goquery.Document) keyFacts {
+	facts := keyFacts{}
+	for _, title := range keyFactSectionTitles {
+		section := doc.Find(fmt.Sprintf(keyFactSectionSelector, title))
+		section.Find(keyFactRowSelector).Each(func(_ int, rowHtml *goquery.Selection) {
+			label := normalizeLabel(rowHtml.Find(labelCellSelector).First().Text())
+			value := strings.Join(strings.Fields(rowHtml.Find(dataCellSelector).First().Text()), " ")
+			if label == "" || value == "" {
+				return
+			}
+			if _, ok := facts[label]; !ok {
+				facts[label] = value
+			}
+		})
+	}
+	return facts
 }
 
-func (u *DailyDataUpdater) findHoldings(doc *goquery.Document) ([]models.Holding, error) {
+// get returns the value of the key fact, key is one of keyFactLabels.
+func (f keyFacts) get(key string) string {
+	for _, label := range keyFactLabels[key] {
+		if value, ok := f[label]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// amount returns the value of the key fact as a number, nil when it is missing.
+func (f keyFacts) amount(key string) *float64 {
+	if n, ok := parseAmount(f.get(key)); ok {
+		return &n
+	}
+	return nil
+}
+
+// findCharacteristics extracts the key facts of the fund page. It returns nil
+// when the page shows none of them.
+func (u *DailyDataUpdater) findCharacteristics(facts keyFacts) *models.FundCharacteristics {
+	var characteristics models.FundCharacteristics
+
+	characteristics.NAV = facts.amount("nav")
+	characteristics.MarketPrice = facts.amount("market_price")
+	characteristics.GrossExpenseRatio = facts.amount("gross_expense_ratio")
+	characteristics.AUM = facts.amount("aum")
+
+	if n := facts.amount("number_of_holdings"); n != nil {
+		holdings := int(*n)
+		characteristics.NumberOfHoldings = &holdings
+	}
+
+	if date, ok := parseDate(facts.get("inception_date")); ok {
+		characteristics.InceptionDate = date
+	}
+
+	characteristics.Benchmark = facts.get("benchmark")
+	characteristics.PrimaryExchange = facts.get("primary_exchange")
+
+	if characteristics == (models.FundCharacteristics{}) {
+		return nil
+	}
+
+	return &characteristics
+}
+
+// findFixedIncome extracts the duration, yields, credit quality and maturity
+// ladder of the fund page. It returns nil when the page shows none of them.
+func (u *DailyDataUpdater) findFixedIncome(doc *goquery.Document, facts keyFacts) *models.FixedIncome {
+	var fixedIncome models.FixedIncome
+
+	fixedIncome.Duration = facts.amount("duration")
+	fixedIncome.YieldToMaturity = facts.amount("yield_to_maturity")
+	fixedIncome.YieldToWorst = facts.amount("yield_to_worst")
+
+	if div := doc.Find(creditQualityDivSelector); div.Length() != 0 {
+		fixedIncome.CreditQuality = u.findWeights(div)
+	}
+	if div := doc.Find(maturityLadderDivSelector); div.Length() != 0 {
+		fixedIncome.MaturityLadder = u.findWeights(div)
+	}
+
+	if fixedIncome.Duration == nil && fixedIncome.YieldToMaturity == nil && fixedIncome.YieldToWorst == nil &&
+		len(fixedIncome.CreditQuality) == 0 && len(fixedIncome.MaturityLadder) == 0 {
+		return nil
+	}
+
+	return &fixedIncome
+}
+
+// findAssetClass reads the asset class key fact. Pages without it are taken
+// for fixed income funds when they show bond characteristics, and for equity
+// funds otherwise, the only kind the updater scraped before.
+func findAssetClass(facts keyFacts, fixedIncome *models.FixedIncome) models.AssetClass {
+	label := strings.ToLower(facts.get("asset_class"))
+
+	switch {
+	case strings.Contains(label, "multi"):
+		return models.AssetClassMultiAsset
+	case strings.Contains(label, "fixed income"), strings.Contains(label, "bond"):
+		return models.AssetClassFixedIncome
+	case strings.Contains(label, "equity"), strings.Contains(label, "stock"):
+		return models.AssetClassEquity
+	case strings.Contains(label, "commodit"), strings.Contains(label, "gold"):
+		return models.AssetClassCommodity
+	case strings.Contains(label, "alternative"):
+		return models.AssetClassAlternative
+	case fixedIncome != nil:
+		return models.AssetClassFixedIncome
+	default:
+		return models.AssetClassEquity
+	}
+}
+
+// normalizeLabel lowercases a label cell and drops footnote markers and colons,
+// e.g. "Gross Expense Ratio1:" becomes "gross expense ratio".
+func normalizeLabel(label string) string {
+	label = strings.ToLower(strings.Join(strings.Fields(label), " "))
+	return strings.TrimRight(label, "0123456789*†: ")
+}
+
+// findHoldings returns the top holdings of the fund and of its benchmark index.
+func (u *DailyDataUpdater) findHoldings(doc *goquery.Document) ([]models.Holding, []models.Holding, error) {
 	// Find the section containing the top holdings information with an <h3> element containing 'Top Holdings'.
 	div := doc.Find(topHoldingsSectionSelector)
 
 	// Check if the section exists
 	if div.Length() == 0 {
-		return nil, ErrNotFound
+		return nil, nil, ErrNotFound
 	}
 
-	// Create a slice to store FundHoldings
-	var fundHoldings []models.Holding
+	// Create slices to store the fund and index holdings
+	var fundHoldings, indexHoldings []models.Holding
 
 	// Iterate over the rows of the table, starting from the second row (skipping the header)
 	div.Find(tableSelector).Find("tr").Each(func(index int, rowHtml *goquery.Selection) {
 		if index > 0 && rowHtml.Find(labelCellSelector).Text() != "" {
 			// Extract data from the cells in the row
-			// we select Fund Top Holdings it's mean rowHtml.Find(dataCellSelector).Eq(1).Text() shouldn't be empty
-			// rowHtml.Find(dataCellSelector).Eq(1).Text() empty for Index Top Holdings
-			if rowHtml.Find(dataCellSelector).Eq(1).Text() != "" {
-				holdingName := rowHtml.Find(labelCellSelector).Text()
-				sharesHeld := rowHtml.Find(dataCellSelector).Eq(0).Text()
-				weight := rowHtml.Find(dataCellSelector).Eq(1).Text()
-
-				// Create a FundHoldings object and append it to the slice
-				holding := models.Holding{
+			holdingName := rowHtml.Find(labelCellSelector).Text()
+			dataCells := rowHtml.Find(dataCellSelector)
+
+			// Fund Top Holdings show the shares held and the weight,
+			// Index Top Holdings only the weight, their second data cell is empty
+			if dataCells.Eq(1).Text() != "" {
+				fundHoldings = append(fundHoldings, models.Holding{
 					Name:       holdingName,
-					SharesHeld: sharesHeld,
-					Weight:     weight,
-				}
-				fundHoldings = append(fundHoldings, holding)
+					SharesHeld: dataCells.Eq(0).Text(),
+					Weight:     dataCells.Eq(1).Text(),
+				})
+			} else if dataCells.Eq(0).Text() != "" {
+				indexHoldings = append(indexHoldings, models.Holding{
+					Name:   holdingName,
+					Weight: dataCells.Eq(0).Text(),
+				})
 			}
 		}
 	})
 
-	return fundHoldings, nil
+	return fundHoldings, indexHoldings, nil
 }
 
-func (u *DailyDataUpdater) findSectors(doc *goquery.Document) ([]models.WeightData, error) {
-	sectorDiv := &goquery.Selection{}
-	sectorDivs := []string{
-		sectorBreakdownDivSelector,
-		sectorIndustryDivSelector,
-		sectorSubIndustryDivSelector,
-		sectorFundBreakdownDivSelector,
-	}
+// breakdownSelectors maps every breakdown level to the div showing it.
+var breakdownSelectors = map[models.BreakdownLevel]string{
+	models.BreakdownSector:      sectorBreakdownDivSelector,
+	models.BreakdownIndustry:    sectorIndustryDivSelector,
+	models.BreakdownSubIndustry: sectorSubIndustryDivSelector,
+	models.BreakdownFundSector:  sectorFundBreakdownDivSelector,
+}
+
+// findBreakdowns returns every breakdown the fund page shows in the order of
+// models.BreakdownLevels, and ErrNotFound when it shows none.
+func (u *DailyDataUpdater) findBreakdowns(doc *goquery.Document) ([]models.Breakdown, error) {
+	var breakdowns []models.Breakdown
 
-	for i := range sectorDivs {
-		sectorDiv = doc.Find(sectorDivs[i])
-		if sectorDiv.Length() != 0 {
-			break
+	for _, level := range models.BreakdownLevels {
+		sectorDiv := doc.Find(breakdownSelectors[level])
+		if sectorDiv.Length() == 0 {
+			continue
 		}
+
+		breakdowns = append(breakdowns, models.Breakdown{
+			Level:   level,
+			Weights: u.findWeights(sectorDiv),
+		})
 	}
 
-	// Check if the div exists
-	if sectorDiv.Length() == 0 {
+	// Check if any of the divs exists
+	if len(breakdowns) == 0 {
 		return nil, ErrNotFound
 	}
 
+	return breakdowns, nil
+}
+
+// sectorWeights picks the breakdown kept as ETFData.Sectors, the sector
+// breakdown or the closest level to it the fund page shows.
+func sectorWeights(breakdowns []models.Breakdown) []models.WeightData {
+	preferred := []models.BreakdownLevel{
+		models.BreakdownSector,
+		models.BreakdownFundSector,
+		models.BreakdownIndustry,
+		models.BreakdownSubIndustry,
+	}
+
+	for _, level := range preferred {
+		for _, breakdown := range breakdowns {
+			if breakdown.Level == level {
+				return breakdown.Weights
+			}
+		}
+	}
+
+	return nil
+}
+
+// findWeights reads the label and weight of every row of the tables in sectorDiv.
+func (u *DailyDataUpdater) findWeights(sectorDiv *goquery.Selection) []models.WeightData {
 	sectors := []models.WeightData{}
 
 	// Iterate over the rows of the table, starting from the second row (skipping the header)
@@ -296,7 +749,82 @@ func (u *DailyDataUpdater) findSectors(doc *goquery.Document) ([]models.WeightDa
 		}
 	})
 
-	return sectors, nil
+	return sectors
+}
+
+// distributionColumnLabels lists the header cells each column of the
+// distribution table is shown with, normalized by normalizeLabel.
+var distributionColumnLabels = map[string][]string{
+	"ex_date":     {"ex-date", "ex date", "ex-dividend date", "ex dividend date"},
+	"record_date": {"record date"},
+	"pay_date":    {"pay date", "payable date", "payment date"},
+	"amount":      {"amount", "total distribution", "distribution amount", "amount per share", "distribution per share"},
+	"type":        {"type", "distribution type"},
+}
+
+// findDistributions reads the distribution table of the fund page, funds
+// that did not distribute yet have none. Rows without a valid ex-date or
+// amount are skipped.
+func (u *DailyDataUpdater) findDistributions(doc *goquery.Document) []models.Distribution {
+	var distributions []models.Distribution
+
+	doc.Find(distributionTableSelector).EachWithBreak(func(_ int, tableHtml *goquery.Selection) bool {
+		rows := tableHtml.Find("tr")
+
+		// The first row names the columns
+		columns := map[string]int{}
+		rows.First().Find("th, td").Each(func(index int, cellHtml *goquery.Selection) {
+			label := normalizeLabel(cellHtml.Text())
+			for key, labels := range distributionColumnLabels {
+				for _, candidate := range labels {
+					if _, ok := columns[key]; !ok && label == candidate {
+						columns[key] = index
+					}
+				}
+			}
+		})
+
+		_, hasExDate := columns["ex_date"]
+		_, hasAmount := columns["amount"]
+		if !hasExDate || !hasAmount {
+			return true
+		}
+
+		rows.Slice(1, rows.Length()).Each(func(_ int, rowHtml *goquery.Selection) {
+			cells := rowHtml.Find("th, td")
+			cell := func(key string) string {
+				index, ok := columns[key]
+				if !ok {
+					return ""
+				}
+				return strings.Join(strings.Fields(cells.Eq(index).Text()), " ")
+			}
+
+			exDate, ok := parseDate(cell("ex_date"))
+			if !ok {
+				return
+			}
+			amount, ok := parseAmount(cell("amount"))
+			if !ok {
+				return
+			}
+
+			distribution := models.Distribution{
+				ExDate: exDate,
+				Amount: amount,
+				Type:   strings.Trim(cell("type"), "-"),
+			}
+			distribution.RecordDate, _ = parseDate(cell("record_date"))
+			distribution.PayDate, _ = parseDate(cell("pay_date"))
+
+			distributions = append(distributions, distribution)
+		})
+
+		// Only the first distribution table is read
+		return false
+	})
+
+	return distributions
 }
 
 func (u *DailyDataUpdater) findCountries(doc *goquery.Document) ([]models.WeightData, error) {
@@ -355,7 +883,7 @@ func (u *DailyDataUpdater) getPaths() (map[string]struct{}, error) {
 	}
 
 	// Navigate to the target URL
-	if _, err := page.Goto(u.host+etfFinerWithFilter, playwright.PageGotoOptions{
+	if _, err := page.Goto(u.host+etfFinder, playwright.PageGotoOptions{
 		WaitUntil: playwright.WaitUntilStateNetworkidle,
 	}); err != nil {
 		return nil, fmt.Errorf("could not navigate to the URL, err: %v", err)
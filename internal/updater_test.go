@@ -0,0 +1,296 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"awesomeProject/models"
+)
+
+// failingStore is a MemoryStore whose UpsertMany fails from call failFrom on,
+// and whose holdings and distributions can not be stored when failExtras is set.
+type failingStore struct {
+	*MemoryStore
+	failFrom   int
+	failExtras bool
+	calls      int
+}
+
+var errTestStore = errors.New("store failed")
+
+func (s *failingStore) UpsertMany(etfs []models.ETF) error {
+	s.calls++
+	if s.failFrom > 0 && s.calls >= s.failFrom {
+		return errTestStore
+	}
+	return s.MemoryStore.UpsertMany(etfs)
+}
+
+func (s *failingStore) ReplaceHoldings(holdings []models.FullHoldings) error {
+	if s.failExtras {
+		return errTestStore
+	}
+	return s.MemoryStore.ReplaceHoldings(holdings)
+}
+
+func (s *failingStore) UpsertDistributions(distributions []models.ETFDistributions) error {
+	if s.failExtras {
+		return errTestStore
+	}
+	return s.MemoryStore.UpsertDistributions(distributions)
+}
+
+// scrapedTestETFs returns count scraped ETFs named ETF0, ETF1, ... with a
+// position and a distribution each.
+func scrapedTestETFs(count int) []scrapedETF {
+	etfs := make([]scrapedETF, count)
+	for i := range etfs {
+		ticker := fmt.Sprintf("ETF%d", i)
+		etfs[i] = scrapedETF{
+			data:          models.ETFData{Name: ticker},
+			holdings:      &models.FullHoldings{Ticker: ticker, Holdings: []models.FullHolding{{Name: "Cash"}}},
+			distributions: []models.Distribution{{ExDate: "2024-03-15", Amount: 1}},
+		}
+	}
+	return etfs
+}
+
+// runStoreETFs passes etfs to storeETFs of an updater writing to store.
+func runStoreETFs(store ETFStore, updates *UpdateBroker, config UpdaterConfig, etfs []scrapedETF) updateResult {
+	updater := NewDailyDataUpdater("", store, updates, config, newTestLogger())
+
+	parsed := make(chan scrapedETF, len(etfs))
+	for _, etf := range etfs {
+		parsed <- etf
+	}
+	close(parsed)
+
+	return updater.storeETFs(parsed)
+}
+
+func storedTestIDs(t *testing.T, store ETFStore) int {
+	t.Helper()
+
+	ids, err := store.GetAllIDs()
+	if err != nil {
+		t.Fatalf("GetAllIDs: %v", err)
+	}
+	return len(ids)
+}
+
+func TestStoreETFsInBatches(t *testing.T) {
+	store := &failingStore{MemoryStore: NewMemoryStore()}
+	updates := NewUpdateBroker()
+	published, unsubscribe := updates.Subscribe(nil)
+	defer unsubscribe()
+
+	result := runStoreETFs(store, updates, UpdaterConfig{BatchSize: 2}, scrapedTestETFs(5))
+
+	if result.err != nil || result.stored != 5 || result.failed != 0 {
+		t.Fatalf("result = %+v, want 5 stored", result)
+	}
+	if store.calls != 3 {
+		t.Errorf("UpsertMany was called %d times, want 3 for batches of 2", store.calls)
+	}
+	if n := storedTestIDs(t, store); n != 5 {
+		t.Errorf("%d ETFs stored, want 5", n)
+	}
+	if page, err := store.ListHoldings("ETF4", 10, 0); err != nil || page.Total != 1 {
+		t.Errorf("ListHoldings = %+v, %v", page, err)
+	}
+	if distributions, err := store.ListDistributions("ETF4", "", ""); err != nil || len(distributions) != 1 {
+		t.Errorf("ListDistributions = %+v, %v", distributions, err)
+	}
+	if len(published) != 5 {
+		t.Errorf("%d updates published, want 5", len(published))
+	}
+}
+
+func TestStoreETFsFailedBatchFailsTheRun(t *testing.T) {
+	store := &failingStore{MemoryStore: NewMemoryStore(), failFrom: 2}
+	updates := NewUpdateBroker()
+	published, unsubscribe := updates.Subscribe(nil)
+	defer unsubscribe()
+
+	result := runStoreETFs(store, updates, UpdaterConfig{BatchSize: 2}, scrapedTestETFs(5))
+
+	if result.err == nil {
+		t.Fatal("a failed batch did not fail the run")
+	}
+	if result.stored != 2 || result.failed != 3 {
+		t.Errorf("result = %+v, want 2 stored and 3 failed", result)
+	}
+
+	// The batch stored before the failure is kept
+	if n := storedTestIDs(t, store); n != 2 {
+		t.Errorf("%d ETFs stored, want 2", n)
+	}
+	if len(published) != 2 {
+		t.Errorf("%d updates published, want only the 2 stored", len(published))
+	}
+}
+
+func TestStoreETFsKeepsETFsWhenHoldingsFail(t *testing.T) {
+	store := &failingStore{MemoryStore: NewMemoryStore(), failExtras: true}
+
+	result := runStoreETFs(store, nil, UpdaterConfig{BatchSize: 2}, scrapedTestETFs(3))
+
+	if result.err != nil || result.stored != 3 {
+		t.Fatalf("result = %+v, want 3 stored", result)
+	}
+	if _, err := store.ListHoldings("ETF0", 10, 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ListHoldings = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStoreETFsAtomic(t *testing.T) {
+	store := &failingStore{MemoryStore: NewMemoryStore()}
+
+	result := runStoreETFs(store, nil, UpdaterConfig{BatchSize: 2, Atomic: true}, scrapedTestETFs(5))
+
+	if result.err != nil || result.stored != 5 {
+		t.Fatalf("result = %+v, want 5 stored", result)
+	}
+	if store.calls != 0 {
+		t.Errorf("UpsertMany was called %d times, an atomic run stores everything with StoreScraped", store.calls)
+	}
+	if n := storedTestIDs(t, store); n != 5 {
+		t.Errorf("%d ETFs stored, want 5", n)
+	}
+	if page, err := store.ListHoldings("ETF0", 10, 0); err != nil || page.Total != 1 {
+		t.Errorf("ListHoldings = %+v, %v", page, err)
+	}
+	if distributions, err := store.ListDistributions("ETF0", "", ""); err != nil || len(distributions) != 1 {
+		t.Errorf("ListDistributions = %+v, %v", distributions, err)
+	}
+}
+
+func TestStoreETFsAtomicFailureKeepsThePreviousData(t *testing.T) {
+	store := &failingStore{MemoryStore: NewMemoryStore()}
+	seedTestETFs(t, store.MemoryStore)
+
+	// Holdings of an ETF that is neither stored nor part of the run fail the transaction
+	etfs := scrapedTestETFs(3)
+	etfs[2].holdings.Ticker = "NONE"
+
+	result := runStoreETFs(store, nil, UpdaterConfig{Atomic: true}, etfs)
+
+	if result.err == nil || result.stored != 0 || result.failed != 3 {
+		t.Fatalf("result = %+v, want 3 failed", result)
+	}
+	if n := storedTestIDs(t, store); n != len(testETFs()) {
+		t.Errorf("%d ETFs stored, want only the %d stored before", n, len(testETFs()))
+	}
+	if page, err := store.ListHoldings("SPY", 10, 0); err != nil || page.Total != 3 {
+		t.Errorf("ListHoldings of the previous data = %+v, %v", page, err)
+	}
+}
+
+// parseTestPage parses the fund page saved as testdata/name.
+func parseTestPage(t *testing.T, name string) *models.ETFData {
+	t.Helper()
+
+	file, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	updater := NewDailyDataUpdater("", nil, nil, DefaultUpdaterConfig(), newTestLogger())
+	etf, err := updater.ParseETF(file)
+	if err != nil {
+		t.Fatalf("ParseETF(%s): %v", name, err)
+	}
+	return etf
+}
+
+func TestParseETFKeyFacts(t *testing.T) {
+	etf := parseTestPage(t, "equity_fund.html")
+
+	if etf.Name != "SPY" || etf.AssetClass != models.AssetClassEquity || etf.FixedIncome != nil {
+		t.Errorf("name %q, asset class %q, fixed income %+v", etf.Name, etf.AssetClass, etf.FixedIncome)
+	}
+
+	c := etf.Characteristics
+	if c == nil {
+		t.Fatal("no characteristics")
+	}
+
+	amounts := []struct {
+		name string
+		got  *float64
+		want float64
+	}{
+		{"NAV", c.NAV, 512.34},
+		{"market price", c.MarketPrice, 512.40},
+		{"gross expense ratio", c.GrossExpenseRatio, 0.0945},
+		{"AUM", c.AUM, 513541.07e6},
+	}
+	for _, amount := range amounts {
+		if amount.got == nil || *amount.got != amount.want {
+			t.Errorf("%s = %v, want %v", amount.name, amount.got, amount.want)
+		}
+	}
+
+	// The index characteristics show other values for the same labels
+	if c.NumberOfHoldings == nil || *c.NumberOfHoldings != 503 {
+		t.Errorf("number of holdings = %v, want 503", c.NumberOfHoldings)
+	}
+	if c.InceptionDate != "1993-01-22" || c.Benchmark != "S&P 500 Index" || c.PrimaryExchange != "NYSE ARCA" {
+		t.Errorf("inception date %q, benchmark %q, primary exchange %q", c.InceptionDate, c.Benchmark, c.PrimaryExchange)
+	}
+
+	if len(etf.TopHoldings) != 2 || len(etf.IndexTopHoldings) != 1 || len(etf.Sectors) != 2 || len(etf.Countries) != 1 {
+		t.Errorf("%d top holdings, %d index top holdings, %d sectors, %d countries",
+			len(etf.TopHoldings), len(etf.IndexTopHoldings), len(etf.Sectors), len(etf.Countries))
+	}
+}
+
+func TestParseETFFixedIncome(t *testing.T) {
+	// The page shows no sector breakdown and no countries, nor an asset class
+	etf := parseTestPage(t, "bond_fund.html")
+
+	if etf.Name != "SPAB" || etf.AssetClass != models.AssetClassFixedIncome {
+		t.Errorf("name %q, asset class %q, want %q", etf.Name, etf.AssetClass, models.AssetClassFixedIncome)
+	}
+	if len(etf.Breakdowns) != 0 || etf.Sectors != nil || etf.Countries != nil || len(etf.TopHoldings) != 1 {
+		t.Errorf("%d breakdowns, sectors %v, countries %v, %d top holdings",
+			len(etf.Breakdowns), etf.Sectors, etf.Countries, len(etf.TopHoldings))
+	}
+
+	f := etf.FixedIncome
+	if f == nil {
+		t.Fatal("no fixed income characteristics")
+	}
+
+	amounts := []struct {
+		name string
+		got  *float64
+		want float64
+	}{
+		{"duration", f.Duration, 5.94},
+		{"yield to maturity", f.YieldToMaturity, 4.65},
+		{"yield to worst", f.YieldToWorst, 4.61},
+	}
+	for _, amount := range amounts {
+		if amount.got == nil || *amount.got != amount.want {
+			t.Errorf("%s = %v, want %v", amount.name, amount.got, amount.want)
+		}
+	}
+
+	wantCredit := []models.WeightData{{Name: "AAA", Weight: "3.12%"}, {Name: "AA", Weight: "72.40%"}, {Name: "A", Weight: "11.55%"}, {Name: "BBB", Weight: "12.93%"}}
+	if !reflect.DeepEqual(f.CreditQuality, wantCredit) {
+		t.Errorf("credit quality = %+v, want %+v", f.CreditQuality, wantCredit)
+	}
+	if len(f.MaturityLadder) != 4 || f.MaturityLadder[3] != (models.WeightData{Name: "10+ Years", Weight: "27.70%"}) {
+		t.Errorf("maturity ladder = %+v", f.MaturityLadder)
+	}
+
+	if c := etf.Characteristics; c == nil || c.NumberOfHoldings == nil || *c.NumberOfHoldings != 8312 || c.NAV == nil || *c.NAV != 25.71 {
+		t.Errorf("characteristics = %+v", c)
+	}
+}
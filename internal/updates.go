@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"strings"
+	"sync"
+
+	"awesomeProject/models"
+)
+
+// updateBufferSize is how many updates a subscriber may fall behind before updates are dropped for it.
+const updateBufferSize = 64
+
+// UpdateBroker passes the ETFs stored by the updater on to subscribers,
+// e.g. WatchUpdates streams of the gRPC service.
+type UpdateBroker struct {
+	mu          sync.Mutex
+	subscribers map[*updateSubscriber]struct{}
+}
+
+type updateSubscriber struct {
+	tickers map[string]bool
+	updates chan models.ETFUpdate
+}
+
+func NewUpdateBroker() *UpdateBroker {
+	return &UpdateBroker{
+		subscribers: map[*updateSubscriber]struct{}{},
+	}
+}
+
+// Subscribe returns a channel receiving the updates of the tickers, or of all
+// ETFs when no tickers are given. The returned function ends the subscription
+// and closes the channel.
+func (b *UpdateBroker) Subscribe(tickers []string) (<-chan models.ETFUpdate, func()) {
+	sub := &updateSubscriber{updates: make(chan models.ETFUpdate, updateBufferSize)}
+	if len(tickers) > 0 {
+		sub.tickers = map[string]bool{}
+		for _, ticker := range tickers {
+			sub.tickers[strings.ToUpper(ticker)] = true
+		}
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub.updates, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, sub)
+			b.mu.Unlock()
+			close(sub.updates)
+		})
+	}
+}
+
+// Publish sends an update to every interested subscriber. Subscribers that
+// are too slow miss updates instead of holding up the updater.
+func (b *UpdateBroker) Publish(update models.ETFUpdate) {
+	ticker := strings.ToUpper(update.ETF.Name)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		if sub.tickers != nil && !sub.tickers[ticker] {
+			continue
+		}
+
+		select {
+		case sub.updates <- update:
+		default:
+		}
+	}
+}
@@ -1,66 +1,152 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
 	"os"
-	
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/sirupsen/logrus"
 
 	"awesomeProject/internal"
 )
 
-const (
-	// all of these variables should be set as environment variables
-	dbHost     = "127.0.0.1"
-	dbPort     = "5432"
-	dbUser     = "admin"
-	dbPassword = "admin"
-	dbName     = "database"
-	serverAddr = ":8080"
-	dns	   = "https://www.ssga.com"
-	jwtSecret  = "something"
-)
+// shutdownTimeout is how long running requests may take to finish on SIGTERM.
+const shutdownTimeout = 30 * time.Second
 
 func main() {
 	// Initialize a logger
 	logger := logrus.New()
 
+	// Read the configuration from the environment
+	config := internal.LoadConfig()
+
 	// Create a new database connection
-	store, err := internal.NewDatabase(dbHost, dbPort, dbUser, dbPassword, dbName)
+	store, err := config.OpenStore()
 	if err != nil {
 		logger.Fatalf("Failed to create a database connection: %v", err)
 	}
 
-	// Get the current working directory
-	dir, err := os.Getwd()
-	if err != nil {
-		logger.Fatalf("Error getting the current working directory: %v", err)
-		return
+	// Run database migrations, the memory store has none
+	if migrator, ok := store.(internal.Migrator); ok && config.AutoMigrate {
+		err = migrator.RunMigrations()
+		if err != nil {
+			logger.Fatalf("Failed to run database migrations: %v", err)
+		}
 	}
 
-	// Run database migrations
-	err = store.RunMigrations(dir + "/migrations")
-	if err != nil {
-		logger.Fatalf("Failed to run database migrations: %v", err)
-	}
+	// Pass the data stored by the updater on to WatchUpdates streams
+	updates := internal.NewUpdateBroker()
 
 	// Create a new DailyDataUpdater instance
-	ddu := internal.NewDailyDataUpdater(dns , store, logger)
+	ddu := internal.NewDailyDataUpdater(config.SourceHost, store, updates, config.UpdaterConfig(), logger)
 
 	go ddu.Run()
 
 	// Create a new server
-	server := internal.NewServer(logger, store)
+	server := internal.NewServer(logger, store, updates)
+
+	// Create the first admin user, or replace the default password of the seeded one
+	err = server.BootstrapAdmin(config.AdminUsername, config.AdminPassword)
+	if err != nil {
+		logger.Fatalf("Failed to bootstrap the admin user: %v", err)
+	}
+
+	// Create a rate limiter that keeps its daily quotas in the database
+	limiter := internal.NewRateLimiter(config.RateLimits, store, logger)
+
+	// Load the keys access tokens are signed with
+	keys, err := config.KeySet()
+	if err != nil {
+		logger.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+
+	// Reload the keys on SIGHUP to rotate them without a restart
+	if config.JWTKeysDir != "" {
+		go reloadKeysOnSignal(keys, logger)
+	}
+
+	// Track failed logins to slow down brute-force attempts
+	throttle := internal.NewLoginThrottle(internal.DefaultLoginThrottleConfig(), logger)
+
+	// Report the database, migration and data freshness state to the orchestrator
+	health := internal.NewHealthChecker(store, ddu, config.ReadyMaxDataAge, logger)
 
 	// Create HTTP handlers
-	handlers := internal.NewHandler(server, jwtSecret)
+	handlers := internal.NewHandler(server, keys, limiter, throttle, health)
 
 	// Create a router and set up routes
-	r := internal.MakeHTTPHandler(handlers)
-
-	// Start the HTTP server
-	err = http.ListenAndServe(serverAddr, r)
+	r, err := internal.MakeHTTPHandler(handlers)
 	if err != nil {
+		logger.Fatalf("Failed to create the HTTP handler: %v", err)
+	}
+
+	// Serve the gRPC API on its own port
+	go serveGRPC(config.GRPCAddr, handlers, logger)
+
+	// Report routes that are missing in the OpenAPI document or vice versa
+	if err = internal.CheckOpenAPIRoutes(r); err != nil {
+		logger.Warn(err)
+	}
+
+	// Start the HTTP server, it stops on SIGTERM or SIGINT after the running requests finished
+	httpServer := &http.Server{Addr: config.ServerAddr, Handler: r}
+	stopped := make(chan struct{})
+	go shutdownOnSignal(httpServer, stopped, logger)
+
+	err = httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		logger.Fatalf("Failed to start the HTTP server: %v", err)
 	}
+	<-stopped
+
+	// Write the queued audit events before exiting
+	server.Close()
+	logger.Info("Server stopped")
+}
+
+func shutdownOnSignal(httpServer *http.Server, stopped chan<- struct{}, logger *logrus.Logger) {
+	defer close(stopped)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	<-signals
+
+	logger.Info("Shutting down, waiting for running requests to finish")
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(ctx); err != nil {
+		logger.Errorf("Failed to shut down the HTTP server: %v", err)
+	}
+}
+
+func serveGRPC(addr string, handlers *internal.Handlers, logger *logrus.Logger) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		logger.Fatalf("Failed to listen for gRPC connections: %v", err)
+	}
+
+	err = internal.NewGRPCServer(handlers).Serve(listener)
+	if err != nil {
+		logger.Fatalf("Failed to start the gRPC server: %v", err)
+	}
+}
+
+func reloadKeysOnSignal(keys *internal.KeySet, logger *logrus.Logger) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	for range signals {
+		if err := keys.Reload(); err != nil {
+			logger.Errorf("Failed to reload JWT signing keys, keeping the old ones: %v", err)
+			continue
+		}
+		logger.Info("JWT signing keys reloaded")
+	}
 }
@@ -13,10 +13,6 @@ CREATE TABLE IF NOT EXISTS users (
     updated_at TIMESTAMP
 );
 
--- Add a sample user to the 'users' table
-INSERT INTO users (username, password) VALUES
-    ('admin', '8c6976e5b5410415bde908bd4dee15dfb167a9c873fc4bb8a81f6f2ab448a918');
-
 -- Create a trigger function to update the updated_at column
 CREATE OR REPLACE FUNCTION set_updated_at()
 RETURNS TRIGGER AS $$
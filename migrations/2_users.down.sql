@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS users_username_key;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS last_login_at,
+    DROP COLUMN IF EXISTS disabled,
+    DROP COLUMN IF EXISTS email;
@@ -0,0 +1,7 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP;
+
+-- Usernames are used to log in, so they have to be unique
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_key ON users (username);
@@ -0,0 +1,6 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role VARCHAR(32) NOT NULL DEFAULT 'viewer'
+        CHECK (role IN ('viewer', 'analyst', 'admin'));
+
+-- Databases created while 1_init seeded an admin user keep its access to the admin endpoints
+UPDATE users SET role = 'admin' WHERE username = 'admin';
@@ -0,0 +1,7 @@
+-- Daily request counters shared by all replicas
+CREATE TABLE IF NOT EXISTS rate_quotas (
+    client_key VARCHAR(255) NOT NULL,
+    day DATE NOT NULL,
+    requests INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (client_key, day)
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMP NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    username VARCHAR(255),
+    ip VARCHAR(64),
+    request_id VARCHAR(64),
+    method VARCHAR(16),
+    path TEXT,
+    status INTEGER,
+    resource VARCHAR(255),
+    details JSONB
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_occurred_at_idx ON audit_events (occurred_at);
+CREATE INDEX IF NOT EXISTS audit_events_username_idx ON audit_events (username, occurred_at);
+CREATE INDEX IF NOT EXISTS audit_events_resource_idx ON audit_events (resource, occurred_at);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS etf_holdings (
+    etf_id VARCHAR(255) NOT NULL REFERENCES etfs (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    as_of DATE,
+    name TEXT NOT NULL,
+    ticker VARCHAR(64),
+    identifier VARCHAR(64),
+    sedol VARCHAR(16),
+    sector VARCHAR(255),
+    shares_held DOUBLE PRECISION,
+    market_value DOUBLE PRECISION,
+    weight DOUBLE PRECISION,
+    currency VARCHAR(16),
+    PRIMARY KEY (etf_id, position)
+);
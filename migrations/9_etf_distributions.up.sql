@@ -0,0 +1,11 @@
+-- An ex-date may carry several distributions, e.g. income and capital gains
+CREATE TABLE IF NOT EXISTS etf_distributions (
+    etf_id VARCHAR(255) NOT NULL REFERENCES etfs (id) ON DELETE CASCADE,
+    ex_date DATE NOT NULL,
+    distribution_type VARCHAR(64) NOT NULL DEFAULT '',
+    record_date DATE,
+    pay_date DATE,
+    amount DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (etf_id, ex_date, distribution_type)
+);
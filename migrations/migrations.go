@@ -0,0 +1,26 @@
+// Package migrations embeds the SQL migrations, so the binaries apply them
+// no matter which directory they are started from.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed *.sql sqlite/*.sql
+var files embed.FS
+
+// Postgres holds the migrations of the PostgreSQL store.
+var Postgres fs.FS = files
+
+// SQLite holds the migrations of the SQLite store.
+var SQLite = mustSub(files, "sqlite")
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		// The directory is embedded above, so this is a programming error
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS etfs;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS users_username_key;
+ALTER TABLE users DROP COLUMN last_login_at;
+ALTER TABLE users DROP COLUMN disabled;
+ALTER TABLE users DROP COLUMN email;
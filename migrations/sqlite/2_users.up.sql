@@ -0,0 +1,6 @@
+ALTER TABLE users ADD COLUMN email TEXT;
+ALTER TABLE users ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN last_login_at DATETIME;
+
+-- Usernames are used to log in, so they have to be unique
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_key ON users (username);
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'viewer'
+    CHECK (role IN ('viewer', 'analyst', 'admin'));
+
+-- Databases created while 1_init seeded an admin user keep its access to the admin endpoints
+UPDATE users SET role = 'admin' WHERE username = 'admin';
@@ -0,0 +1,7 @@
+-- Daily request counters, days are stored as YYYY-MM-DD
+CREATE TABLE IF NOT EXISTS rate_quotas (
+    client_key TEXT NOT NULL,
+    day TEXT NOT NULL,
+    requests INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (client_key, day)
+);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    occurred_at DATETIME NOT NULL,
+    event_type TEXT NOT NULL,
+    username TEXT,
+    ip TEXT,
+    request_id TEXT,
+    method TEXT,
+    path TEXT,
+    status INTEGER,
+    resource TEXT,
+    -- JSON object
+    details TEXT
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_occurred_at_idx ON audit_events (occurred_at);
+CREATE INDEX IF NOT EXISTS audit_events_username_idx ON audit_events (username, occurred_at);
+CREATE INDEX IF NOT EXISTS audit_events_resource_idx ON audit_events (resource, occurred_at);
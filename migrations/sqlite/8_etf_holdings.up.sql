@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS etf_holdings (
+    etf_id TEXT NOT NULL REFERENCES etfs (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    -- YYYY-MM-DD
+    as_of TEXT,
+    name TEXT NOT NULL,
+    ticker TEXT,
+    identifier TEXT,
+    sedol TEXT,
+    sector TEXT,
+    shares_held REAL,
+    market_value REAL,
+    weight REAL,
+    currency TEXT,
+    PRIMARY KEY (etf_id, position)
+);
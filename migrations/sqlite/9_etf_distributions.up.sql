@@ -0,0 +1,12 @@
+-- An ex-date may carry several distributions, e.g. income and capital gains.
+-- Dates are stored as YYYY-MM-DD.
+CREATE TABLE IF NOT EXISTS etf_distributions (
+    etf_id TEXT NOT NULL REFERENCES etfs (id) ON DELETE CASCADE,
+    ex_date TEXT NOT NULL,
+    distribution_type TEXT NOT NULL DEFAULT '',
+    record_date TEXT,
+    pay_date TEXT,
+    amount REAL NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (etf_id, ex_date, distribution_type)
+);
@@ -2,7 +2,7 @@ package models
 
 import (
 	"encoding/json"
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v4"
 	"time"
 )
 
@@ -13,20 +13,85 @@ type ETF struct {
 	UpdatedAt time.Time
 }
 
+// Role - Define the access level of a user
+type Role string
+
+const (
+	// RoleViewer can read ETF data
+	RoleViewer Role = "viewer"
+	// RoleAnalyst can additionally use the export and analytics endpoints
+	RoleAnalyst Role = "analyst"
+	// RoleAdmin can additionally manage users
+	RoleAdmin Role = "admin"
+)
+
+var roleLevels = map[Role]int{
+	RoleViewer:  1,
+	RoleAnalyst: 2,
+	RoleAdmin:   3,
+}
+
+// Valid reports whether the role is one of the known roles.
+func (r Role) Valid() bool {
+	_, ok := roleLevels[r]
+	return ok
+}
+
+// Includes reports whether the role grants at least the access of the other role.
+func (r Role) Includes(other Role) bool {
+	return r.Valid() && roleLevels[r] >= roleLevels[other]
+}
+
 type User struct {
-	ID        int
-	Username  string
-	Password  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          int        `json:"id"`
+	Username    string     `json:"username"`
+	Password    string     `json:"-"`
+	Email       string     `json:"email,omitempty"`
+	Role        Role       `json:"role"`
+	Disabled    bool       `json:"disabled"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// CreateUserRequest - Define a struct for the user creation request body
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email,omitempty"`
+	Role     Role   `json:"role,omitempty"`
+}
+
+// UpdateRoleRequest - Define a struct for the role change request body
+type UpdateRoleRequest struct {
+	Role Role `json:"role"`
+}
+
+// ResetPasswordRequest - Define a struct for the admin password reset request body
+type ResetPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// ChangePasswordRequest - Define a struct for the self-service password change request body
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
 }
 
 type ETFData struct {
-	Name        string       `json:"name"`
-	Description string       `json:"description"`
-	TopHoldings []Holding    `json:"top_holdings"`
-	Countries   []WeightData `json:"countries"`
-	Sectors     []WeightData `json:"sectors,omitempty"`
+	Name            string               `json:"name"`
+	Description     string               `json:"description"`
+	AssetClass      AssetClass           `json:"asset_class,omitempty"`
+	Characteristics *FundCharacteristics `json:"characteristics,omitempty"`
+	TopHoldings     []Holding            `json:"top_holdings"`
+	// IndexTopHoldings are the top holdings of the benchmark index, their SharesHeld is empty
+	IndexTopHoldings []Holding    `json:"index_top_holdings,omitempty"`
+	Countries        []WeightData `json:"countries"`
+	// Sectors is the sector breakdown, or the closest level the fund page shows, kept for clients that predate the breakdowns
+	Sectors    []WeightData `json:"sectors,omitempty"`
+	Breakdowns []Breakdown  `json:"breakdowns,omitempty"`
+	// FixedIncome is only set for funds whose page shows bond characteristics
+	FixedIncome *FixedIncome `json:"fixed_income,omitempty"`
 }
 
 func (m ETFData) ToJson() []byte {
@@ -34,6 +99,47 @@ func (m ETFData) ToJson() []byte {
 	return res
 }
 
+// AssetClass - Define the asset class of a fund
+type AssetClass string
+
+const (
+	AssetClassEquity      AssetClass = "equity"
+	AssetClassFixedIncome AssetClass = "fixed_income"
+	AssetClassMultiAsset  AssetClass = "multi_asset"
+	AssetClassCommodity   AssetClass = "commodity"
+	AssetClassAlternative AssetClass = "alternative"
+)
+
+// FixedIncome - Define a struct for the bond characteristics of a fund page.
+// Values the page does not show are nil or empty.
+type FixedIncome struct {
+	// Duration is the effective or modified duration in years
+	Duration *float64 `json:"duration,omitempty"`
+	// YieldToMaturity and YieldToWorst are in percent
+	YieldToMaturity *float64 `json:"yield_to_maturity,omitempty"`
+	YieldToWorst    *float64 `json:"yield_to_worst,omitempty"`
+	// CreditQuality is the breakdown by credit rating
+	CreditQuality []WeightData `json:"credit_quality,omitempty"`
+	// MaturityLadder is the breakdown by time to maturity
+	MaturityLadder []WeightData `json:"maturity_ladder,omitempty"`
+}
+
+// FundCharacteristics - Define a struct for the key facts of a fund page.
+// Facts the page does not show are nil or empty.
+type FundCharacteristics struct {
+	NAV         *float64 `json:"nav,omitempty"`
+	MarketPrice *float64 `json:"market_price,omitempty"`
+	// GrossExpenseRatio is in percent, e.g. 0.0945
+	GrossExpenseRatio *float64 `json:"gross_expense_ratio,omitempty"`
+	// AUM is in the base currency of the fund, not in millions as shown on the page
+	AUM              *float64 `json:"aum,omitempty"`
+	NumberOfHoldings *int     `json:"number_of_holdings,omitempty"`
+	// InceptionDate is formatted as YYYY-MM-DD
+	InceptionDate   string `json:"inception_date,omitempty"`
+	Benchmark       string `json:"benchmark,omitempty"`
+	PrimaryExchange string `json:"primary_exchange,omitempty"`
+}
+
 type Holding struct {
 	Name       string `json:"name"`
 	SharesHeld string `json:"shares_held"`
@@ -45,6 +151,35 @@ type WeightData struct {
 	Weight string `json:"weight"`
 }
 
+// BreakdownLevel - Define the classification level of a breakdown
+type BreakdownLevel string
+
+const (
+	BreakdownSector      BreakdownLevel = "sector"
+	BreakdownIndustry    BreakdownLevel = "industry"
+	BreakdownSubIndustry BreakdownLevel = "sub_industry"
+	BreakdownFundSector  BreakdownLevel = "fund_sector"
+)
+
+// BreakdownLevels lists the levels in the order the breakdowns are stored in.
+var BreakdownLevels = []BreakdownLevel{BreakdownSector, BreakdownIndustry, BreakdownSubIndustry, BreakdownFundSector}
+
+// Valid reports whether the level is one of the known levels.
+func (l BreakdownLevel) Valid() bool {
+	for _, level := range BreakdownLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// Breakdown - Define a struct for the weights of a fund at one classification level
+type Breakdown struct {
+	Level   BreakdownLevel `json:"level"`
+	Weights []WeightData   `json:"weights"`
+}
+
 type GeographicalData struct {
 	AttributeArray []CountryWeight
 }
@@ -70,8 +205,267 @@ type FundHoldings struct {
 	Weight      string
 }
 
+// FullHoldings - Define a struct for all positions of an ETF as published in its daily holdings file
+type FullHoldings struct {
+	Ticker string `json:"ticker"`
+	// AsOf is the date of the holdings file as YYYY-MM-DD, empty when the file does not show it
+	AsOf     string        `json:"as_of,omitempty"`
+	Holdings []FullHolding `json:"holdings"`
+}
+
+// FullHolding - Define a struct for a position of the daily holdings file of an ETF
+type FullHolding struct {
+	Name   string `json:"name"`
+	Ticker string `json:"ticker,omitempty"`
+	// Identifier is the CUSIP or ISIN of the security
+	Identifier  string   `json:"identifier,omitempty"`
+	SEDOL       string   `json:"sedol,omitempty"`
+	Sector      string   `json:"sector,omitempty"`
+	SharesHeld  *float64 `json:"shares_held,omitempty"`
+	MarketValue *float64 `json:"market_value,omitempty"`
+	// Weight is the share of the fund in percent
+	Weight   *float64 `json:"weight,omitempty"`
+	Currency string   `json:"currency,omitempty"`
+}
+
+// HoldingsPage - Define a struct for a page of the full holdings of an ETF
+type HoldingsPage struct {
+	Ticker   string        `json:"ticker"`
+	AsOf     string        `json:"as_of,omitempty"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+	Total    int           `json:"total"`
+	Holdings []FullHolding `json:"holdings"`
+}
+
+// Distribution - Define a struct for a distribution paid by an ETF, dates are formatted as YYYY-MM-DD
+type Distribution struct {
+	ExDate     string `json:"ex_date"`
+	RecordDate string `json:"record_date,omitempty"`
+	PayDate    string `json:"pay_date,omitempty"`
+	// Amount is per share in the currency of the fund
+	Amount float64 `json:"amount"`
+	// Type tells e.g. income from capital gains, empty when the fund page does not show it
+	Type string `json:"type,omitempty"`
+}
+
+// ETFDistributions - Define a struct for the distributions shown on the page of an ETF
+type ETFDistributions struct {
+	Ticker        string         `json:"ticker"`
+	Distributions []Distribution `json:"distributions"`
+}
+
+// DistributionHistory - Define a struct for the distributions of an ETF within a date range
+type DistributionHistory struct {
+	Ticker        string         `json:"ticker"`
+	From          string         `json:"from,omitempty"`
+	To            string         `json:"to,omitempty"`
+	Distributions []Distribution `json:"distributions"`
+	// TTMAmount is the sum of the distributions with an ex-date in the last 12 months
+	TTMAmount float64 `json:"ttm_amount"`
+	// TTMYield is TTMAmount relative to the NAV in percent, unset when the fund page shows no NAV
+	TTMYield *float64 `json:"ttm_yield,omitempty"`
+}
+
+// ETFUpdate - Define a struct for new data the updater stored for an ETF
+type ETFUpdate struct {
+	ETF       ETFData   `json:"etf"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Overlap - Define a struct for the top holdings two or more ETFs have in common
+type Overlap struct {
+	Tickers []string `json:"tickers"`
+	// Weight is the sum of the smallest weight of every common holding, in percent
+	Weight   float64          `json:"weight"`
+	Holdings []OverlapHolding `json:"holdings"`
+}
+
+// OverlapHolding - Define a struct for a holding of every ETF of an overlap
+type OverlapHolding struct {
+	Name string `json:"name"`
+	// Weight is the smallest weight of the holding in any of the ETFs, in percent
+	Weight  float64            `json:"weight"`
+	Weights map[string]float64 `json:"weights"`
+}
+
+// Tracking - Define a struct for the comparison of the top holdings of an ETF with those of its benchmark index
+type Tracking struct {
+	Ticker    string `json:"ticker"`
+	Benchmark string `json:"benchmark,omitempty"`
+	// FundWeight and IndexWeight are the sums of the top holdings weights, in percent
+	FundWeight  float64 `json:"fund_weight"`
+	IndexWeight float64 `json:"index_weight"`
+	// Deviation is the sum of the absolute differences of the holdings both lists show, in percent
+	Deviation float64           `json:"deviation"`
+	Holdings  []TrackingHolding `json:"holdings"`
+}
+
+// TrackingHolding - Define a struct for the weights of a holding in an ETF and in its benchmark index
+type TrackingHolding struct {
+	Name string `json:"name"`
+	// FundWeight and IndexWeight are unset when the holding is not among the top holdings of the fund or the index
+	FundWeight  *float64 `json:"fund_weight,omitempty"`
+	IndexWeight *float64 `json:"index_weight,omitempty"`
+	// Difference is the fund weight minus the index weight, unset unless both are known
+	Difference *float64 `json:"difference,omitempty"`
+}
+
+// Position - Define a struct for the share of an ETF in a portfolio
+type Position struct {
+	Ticker string  `json:"ticker"`
+	Weight float64 `json:"weight"`
+}
+
+// ExposureRequest - Define a struct for the exposure request body
+type ExposureRequest struct {
+	Positions []Position `json:"positions"`
+}
+
+// Exposure - Define a struct for the combined holdings, sectors and countries of a portfolio
+type Exposure struct {
+	Holdings  []ExposureWeight `json:"holdings"`
+	Sectors   []ExposureWeight `json:"sectors"`
+	Countries []ExposureWeight `json:"countries"`
+}
+
+// ExposureWeight - Define a struct for the share of the portfolio in a holding, sector or country
+type ExposureWeight struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+// Session - Define a struct for a login session backed by a refresh token
+type Session struct {
+	ID               string     `json:"id"`
+	UserID           int        `json:"-"`
+	RefreshTokenHash string     `json:"-"`
+	UserAgent        string     `json:"user_agent,omitempty"`
+	IP               string     `json:"ip,omitempty"`
+	Current          bool       `json:"current"`
+	CreatedAt        time.Time  `json:"created_at"`
+	LastUsedAt       time.Time  `json:"last_used_at"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+}
+
+// LoginRequest - Define a struct for the JSON login request body
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// TokenResponse - Define a struct for the tokens returned on login and refresh
+type TokenResponse struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresIn    int       `json:"expires_in"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token"`
+}
+
+// RefreshRequest - Define a struct for the token refresh request body
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// APIKey - Define a struct for an API key used by machine-to-machine clients
+type APIKey struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"-"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scope      Role       `json:"scope"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateAPIKeyRequest - Define a struct for the API key creation request body
+type CreateAPIKeyRequest struct {
+	Name          string `json:"name"`
+	Scope         Role   `json:"scope,omitempty"`
+	ExpiresInDays int    `json:"expires_in_days,omitempty"`
+}
+
+// CreatedAPIKey - Define a struct for a new API key, the only time the plain key is returned
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// AuditEventType - Define the kind of an audit event
+type AuditEventType string
+
+const (
+	AuditLogin        AuditEventType = "login"
+	AuditLoginFailed  AuditEventType = "login_failed"
+	AuditLoginLockout AuditEventType = "login_lockout"
+	AuditLogout       AuditEventType = "logout"
+	AuditTokenRefresh AuditEventType = "token_refresh"
+	AuditAuthFailed   AuditEventType = "auth_failed"
+	AuditRequest      AuditEventType = "request"
+	AuditAdminAction  AuditEventType = "admin_action"
+	AuditETFRead      AuditEventType = "etf_read"
+	AuditETFExport    AuditEventType = "etf_export"
+)
+
+// AuditEvent - Define a struct for a recorded authentication or data access event
+type AuditEvent struct {
+	ID         int64             `json:"id"`
+	OccurredAt time.Time         `json:"occurred_at"`
+	Type       AuditEventType    `json:"type"`
+	Username   string            `json:"username,omitempty"`
+	IP         string            `json:"ip,omitempty"`
+	RequestID  string            `json:"request_id,omitempty"`
+	Method     string            `json:"method,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	Status     int               `json:"status,omitempty"`
+	Resource   string            `json:"resource,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+}
+
+// AuditFilter - Define a struct for the audit log query parameters
+type AuditFilter struct {
+	Username  string
+	Type      AuditEventType
+	Resource  string
+	RequestID string
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+	Offset    int
+}
+
 // Claims - Define a struct for JWT claims
 type Claims struct {
-	Username string `json:"username"`
-	jwt.StandardClaims
+	Username  string `json:"username"`
+	Role      Role   `json:"role"`
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// UpdaterRun - Define a struct for the outcome of a scraping run of the updater
+type UpdaterRun struct {
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Stored     int        `json:"stored"`
+	Failed     int        `json:"failed"`
+	Succeeded  bool       `json:"succeeded"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Readiness - Define a struct for the readiness check response
+type Readiness struct {
+	Ready                    bool        `json:"ready"`
+	Database                 string      `json:"database"`
+	MigrationVersion         uint        `json:"migration_version,omitempty"`
+	ExpectedMigrationVersion uint        `json:"expected_migration_version,omitempty"`
+	DataUpdatedAt            *time.Time  `json:"data_updated_at,omitempty"`
+	DataAgeSeconds           *int64      `json:"data_age_seconds,omitempty"`
+	MaxDataAgeSeconds        int64       `json:"max_data_age_seconds"`
+	LastUpdate               *UpdaterRun `json:"last_update,omitempty"`
+	Problems                 []string    `json:"problems,omitempty"`
 }
@@ -0,0 +1,4 @@
+// Package etfpb contains the gRPC service generated from etf.proto.
+package etfpb
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative etfpb/etf.proto
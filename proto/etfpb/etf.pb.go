@@ -0,0 +1,1928 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: etfpb/etf.proto
+
+package etfpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListETFsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListETFsRequest) Reset() {
+	*x = ListETFsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListETFsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListETFsRequest) ProtoMessage() {}
+
+func (x *ListETFsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListETFsRequest.ProtoReflect.Descriptor instead.
+func (*ListETFsRequest) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{0}
+}
+
+type ListETFsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tickers []string `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty"`
+}
+
+func (x *ListETFsResponse) Reset() {
+	*x = ListETFsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListETFsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListETFsResponse) ProtoMessage() {}
+
+func (x *ListETFsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListETFsResponse.ProtoReflect.Descriptor instead.
+func (*ListETFsResponse) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListETFsResponse) GetTickers() []string {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+type GetETFRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ticker string `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+}
+
+func (x *GetETFRequest) Reset() {
+	*x = GetETFRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetETFRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetETFRequest) ProtoMessage() {}
+
+func (x *GetETFRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetETFRequest.ProtoReflect.Descriptor instead.
+func (*GetETFRequest) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetETFRequest) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+type WatchUpdatesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only updates of these tickers are sent, all updates when empty. Watching
+	// every ETF or more than 10 needs the analyst role.
+	Tickers []string `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty"`
+}
+
+func (x *WatchUpdatesRequest) Reset() {
+	*x = WatchUpdatesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchUpdatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUpdatesRequest) ProtoMessage() {}
+
+func (x *WatchUpdatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUpdatesRequest.ProtoReflect.Descriptor instead.
+func (*WatchUpdatesRequest) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WatchUpdatesRequest) GetTickers() []string {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+type ETFUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Etf       *ETF                   `protobuf:"bytes,1,opt,name=etf,proto3" json:"etf,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *ETFUpdate) Reset() {
+	*x = ETFUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ETFUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ETFUpdate) ProtoMessage() {}
+
+func (x *ETFUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ETFUpdate.ProtoReflect.Descriptor instead.
+func (*ETFUpdate) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ETFUpdate) GetEtf() *ETF {
+	if x != nil {
+		return x.Etf
+	}
+	return nil
+}
+
+func (x *ETFUpdate) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// ETF mirrors models.ETFData, weights are formatted as on the fund pages, e.g. "7.12%".
+type ETF struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string        `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	TopHoldings []*Holding    `protobuf:"bytes,3,rep,name=top_holdings,json=topHoldings,proto3" json:"top_holdings,omitempty"`
+	Countries   []*WeightData `protobuf:"bytes,4,rep,name=countries,proto3" json:"countries,omitempty"`
+	Sectors     []*WeightData `protobuf:"bytes,5,rep,name=sectors,proto3" json:"sectors,omitempty"`
+	// Unset when the fund page shows none of the key facts.
+	Characteristics *FundCharacteristics `protobuf:"bytes,6,opt,name=characteristics,proto3" json:"characteristics,omitempty"`
+	// Top holdings of the benchmark index, their shares_held is empty.
+	IndexTopHoldings []*Holding `protobuf:"bytes,7,rep,name=index_top_holdings,json=indexTopHoldings,proto3" json:"index_top_holdings,omitempty"`
+	// Every breakdown the fund page shows, sectors is the sector breakdown or
+	// the closest level to it.
+	Breakdowns []*Breakdown `protobuf:"bytes,8,rep,name=breakdowns,proto3" json:"breakdowns,omitempty"`
+	// One of "equity", "fixed_income", "multi_asset", "commodity" and "alternative".
+	AssetClass string `protobuf:"bytes,9,opt,name=asset_class,json=assetClass,proto3" json:"asset_class,omitempty"`
+	// Unset unless the fund page shows bond characteristics.
+	FixedIncome *FixedIncome `protobuf:"bytes,10,opt,name=fixed_income,json=fixedIncome,proto3" json:"fixed_income,omitempty"`
+}
+
+func (x *ETF) Reset() {
+	*x = ETF{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ETF) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ETF) ProtoMessage() {}
+
+func (x *ETF) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ETF.ProtoReflect.Descriptor instead.
+func (*ETF) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ETF) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ETF) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ETF) GetTopHoldings() []*Holding {
+	if x != nil {
+		return x.TopHoldings
+	}
+	return nil
+}
+
+func (x *ETF) GetCountries() []*WeightData {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+func (x *ETF) GetSectors() []*WeightData {
+	if x != nil {
+		return x.Sectors
+	}
+	return nil
+}
+
+func (x *ETF) GetCharacteristics() *FundCharacteristics {
+	if x != nil {
+		return x.Characteristics
+	}
+	return nil
+}
+
+func (x *ETF) GetIndexTopHoldings() []*Holding {
+	if x != nil {
+		return x.IndexTopHoldings
+	}
+	return nil
+}
+
+func (x *ETF) GetBreakdowns() []*Breakdown {
+	if x != nil {
+		return x.Breakdowns
+	}
+	return nil
+}
+
+func (x *ETF) GetAssetClass() string {
+	if x != nil {
+		return x.AssetClass
+	}
+	return ""
+}
+
+func (x *ETF) GetFixedIncome() *FixedIncome {
+	if x != nil {
+		return x.FixedIncome
+	}
+	return nil
+}
+
+// FundCharacteristics mirrors models.FundCharacteristics, facts missing on the fund page are unset.
+type FundCharacteristics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nav         *float64 `protobuf:"fixed64,1,opt,name=nav,proto3,oneof" json:"nav,omitempty"`
+	MarketPrice *float64 `protobuf:"fixed64,2,opt,name=market_price,json=marketPrice,proto3,oneof" json:"market_price,omitempty"`
+	// Gross expense ratio in percent.
+	GrossExpenseRatio *float64 `protobuf:"fixed64,3,opt,name=gross_expense_ratio,json=grossExpenseRatio,proto3,oneof" json:"gross_expense_ratio,omitempty"`
+	// Assets under management in the base currency of the fund.
+	Aum              *float64 `protobuf:"fixed64,4,opt,name=aum,proto3,oneof" json:"aum,omitempty"`
+	NumberOfHoldings *int32   `protobuf:"varint,5,opt,name=number_of_holdings,json=numberOfHoldings,proto3,oneof" json:"number_of_holdings,omitempty"`
+	// Formatted as YYYY-MM-DD.
+	InceptionDate   string `protobuf:"bytes,6,opt,name=inception_date,json=inceptionDate,proto3" json:"inception_date,omitempty"`
+	Benchmark       string `protobuf:"bytes,7,opt,name=benchmark,proto3" json:"benchmark,omitempty"`
+	PrimaryExchange string `protobuf:"bytes,8,opt,name=primary_exchange,json=primaryExchange,proto3" json:"primary_exchange,omitempty"`
+}
+
+func (x *FundCharacteristics) Reset() {
+	*x = FundCharacteristics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FundCharacteristics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FundCharacteristics) ProtoMessage() {}
+
+func (x *FundCharacteristics) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FundCharacteristics.ProtoReflect.Descriptor instead.
+func (*FundCharacteristics) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FundCharacteristics) GetNav() float64 {
+	if x != nil && x.Nav != nil {
+		return *x.Nav
+	}
+	return 0
+}
+
+func (x *FundCharacteristics) GetMarketPrice() float64 {
+	if x != nil && x.MarketPrice != nil {
+		return *x.MarketPrice
+	}
+	return 0
+}
+
+func (x *FundCharacteristics) GetGrossExpenseRatio() float64 {
+	if x != nil && x.GrossExpenseRatio != nil {
+		return *x.GrossExpenseRatio
+	}
+	return 0
+}
+
+func (x *FundCharacteristics) GetAum() float64 {
+	if x != nil && x.Aum != nil {
+		return *x.Aum
+	}
+	return 0
+}
+
+func (x *FundCharacteristics) GetNumberOfHoldings() int32 {
+	if x != nil && x.NumberOfHoldings != nil {
+		return *x.NumberOfHoldings
+	}
+	return 0
+}
+
+func (x *FundCharacteristics) GetInceptionDate() string {
+	if x != nil {
+		return x.InceptionDate
+	}
+	return ""
+}
+
+func (x *FundCharacteristics) GetBenchmark() string {
+	if x != nil {
+		return x.Benchmark
+	}
+	return ""
+}
+
+func (x *FundCharacteristics) GetPrimaryExchange() string {
+	if x != nil {
+		return x.PrimaryExchange
+	}
+	return ""
+}
+
+// FixedIncome mirrors models.FixedIncome, values missing on the fund page are unset.
+type FixedIncome struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Effective or modified duration in years.
+	Duration *float64 `protobuf:"fixed64,1,opt,name=duration,proto3,oneof" json:"duration,omitempty"`
+	// Yields in percent.
+	YieldToMaturity *float64      `protobuf:"fixed64,2,opt,name=yield_to_maturity,json=yieldToMaturity,proto3,oneof" json:"yield_to_maturity,omitempty"`
+	YieldToWorst    *float64      `protobuf:"fixed64,3,opt,name=yield_to_worst,json=yieldToWorst,proto3,oneof" json:"yield_to_worst,omitempty"`
+	CreditQuality   []*WeightData `protobuf:"bytes,4,rep,name=credit_quality,json=creditQuality,proto3" json:"credit_quality,omitempty"`
+	MaturityLadder  []*WeightData `protobuf:"bytes,5,rep,name=maturity_ladder,json=maturityLadder,proto3" json:"maturity_ladder,omitempty"`
+}
+
+func (x *FixedIncome) Reset() {
+	*x = FixedIncome{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FixedIncome) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FixedIncome) ProtoMessage() {}
+
+func (x *FixedIncome) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FixedIncome.ProtoReflect.Descriptor instead.
+func (*FixedIncome) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FixedIncome) GetDuration() float64 {
+	if x != nil && x.Duration != nil {
+		return *x.Duration
+	}
+	return 0
+}
+
+func (x *FixedIncome) GetYieldToMaturity() float64 {
+	if x != nil && x.YieldToMaturity != nil {
+		return *x.YieldToMaturity
+	}
+	return 0
+}
+
+func (x *FixedIncome) GetYieldToWorst() float64 {
+	if x != nil && x.YieldToWorst != nil {
+		return *x.YieldToWorst
+	}
+	return 0
+}
+
+func (x *FixedIncome) GetCreditQuality() []*WeightData {
+	if x != nil {
+		return x.CreditQuality
+	}
+	return nil
+}
+
+func (x *FixedIncome) GetMaturityLadder() []*WeightData {
+	if x != nil {
+		return x.MaturityLadder
+	}
+	return nil
+}
+
+type Holding struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name       string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SharesHeld string `protobuf:"bytes,2,opt,name=shares_held,json=sharesHeld,proto3" json:"shares_held,omitempty"`
+	Weight     string `protobuf:"bytes,3,opt,name=weight,proto3" json:"weight,omitempty"`
+}
+
+func (x *Holding) Reset() {
+	*x = Holding{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Holding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Holding) ProtoMessage() {}
+
+func (x *Holding) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Holding.ProtoReflect.Descriptor instead.
+func (*Holding) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Holding) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Holding) GetSharesHeld() string {
+	if x != nil {
+		return x.SharesHeld
+	}
+	return ""
+}
+
+func (x *Holding) GetWeight() string {
+	if x != nil {
+		return x.Weight
+	}
+	return ""
+}
+
+type WeightData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Weight string `protobuf:"bytes,2,opt,name=weight,proto3" json:"weight,omitempty"`
+}
+
+func (x *WeightData) Reset() {
+	*x = WeightData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WeightData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeightData) ProtoMessage() {}
+
+func (x *WeightData) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeightData.ProtoReflect.Descriptor instead.
+func (*WeightData) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WeightData) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WeightData) GetWeight() string {
+	if x != nil {
+		return x.Weight
+	}
+	return ""
+}
+
+// Breakdown mirrors models.Breakdown, level is one of "sector", "industry",
+// "sub_industry" and "fund_sector".
+type Breakdown struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level   string        `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	Weights []*WeightData `protobuf:"bytes,2,rep,name=weights,proto3" json:"weights,omitempty"`
+}
+
+func (x *Breakdown) Reset() {
+	*x = Breakdown{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Breakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Breakdown) ProtoMessage() {}
+
+func (x *Breakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Breakdown.ProtoReflect.Descriptor instead.
+func (*Breakdown) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Breakdown) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *Breakdown) GetWeights() []*WeightData {
+	if x != nil {
+		return x.Weights
+	}
+	return nil
+}
+
+type GetOverlapRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tickers []string `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty"`
+}
+
+func (x *GetOverlapRequest) Reset() {
+	*x = GetOverlapRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOverlapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOverlapRequest) ProtoMessage() {}
+
+func (x *GetOverlapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOverlapRequest.ProtoReflect.Descriptor instead.
+func (*GetOverlapRequest) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetOverlapRequest) GetTickers() []string {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+type Overlap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tickers []string `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty"`
+	// Sum of the smallest weight of every common holding, in percent.
+	Weight   float64           `protobuf:"fixed64,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	Holdings []*OverlapHolding `protobuf:"bytes,3,rep,name=holdings,proto3" json:"holdings,omitempty"`
+}
+
+func (x *Overlap) Reset() {
+	*x = Overlap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Overlap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Overlap) ProtoMessage() {}
+
+func (x *Overlap) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Overlap.ProtoReflect.Descriptor instead.
+func (*Overlap) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Overlap) GetTickers() []string {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+func (x *Overlap) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Overlap) GetHoldings() []*OverlapHolding {
+	if x != nil {
+		return x.Holdings
+	}
+	return nil
+}
+
+type OverlapHolding struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Smallest weight of the holding in any of the ETFs, in percent.
+	Weight float64 `protobuf:"fixed64,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	// Weight of the holding per ticker, in percent.
+	Weights map[string]float64 `protobuf:"bytes,3,rep,name=weights,proto3" json:"weights,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+}
+
+func (x *OverlapHolding) Reset() {
+	*x = OverlapHolding{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OverlapHolding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OverlapHolding) ProtoMessage() {}
+
+func (x *OverlapHolding) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OverlapHolding.ProtoReflect.Descriptor instead.
+func (*OverlapHolding) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *OverlapHolding) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OverlapHolding) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *OverlapHolding) GetWeights() map[string]float64 {
+	if x != nil {
+		return x.Weights
+	}
+	return nil
+}
+
+type GetExposureRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Positions []*Position `protobuf:"bytes,1,rep,name=positions,proto3" json:"positions,omitempty"`
+}
+
+func (x *GetExposureRequest) Reset() {
+	*x = GetExposureRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetExposureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExposureRequest) ProtoMessage() {}
+
+func (x *GetExposureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExposureRequest.ProtoReflect.Descriptor instead.
+func (*GetExposureRequest) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetExposureRequest) GetPositions() []*Position {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+type Position struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ticker string `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	// Share of the ETF in the portfolio, weights are normalized to a sum of 100.
+	Weight float64 `protobuf:"fixed64,2,opt,name=weight,proto3" json:"weight,omitempty"`
+}
+
+func (x *Position) Reset() {
+	*x = Position{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Position) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Position) ProtoMessage() {}
+
+func (x *Position) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Position.ProtoReflect.Descriptor instead.
+func (*Position) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Position) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *Position) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type Exposure struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Holdings  []*ExposureWeight `protobuf:"bytes,1,rep,name=holdings,proto3" json:"holdings,omitempty"`
+	Sectors   []*ExposureWeight `protobuf:"bytes,2,rep,name=sectors,proto3" json:"sectors,omitempty"`
+	Countries []*ExposureWeight `protobuf:"bytes,3,rep,name=countries,proto3" json:"countries,omitempty"`
+}
+
+func (x *Exposure) Reset() {
+	*x = Exposure{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Exposure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Exposure) ProtoMessage() {}
+
+func (x *Exposure) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Exposure.ProtoReflect.Descriptor instead.
+func (*Exposure) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Exposure) GetHoldings() []*ExposureWeight {
+	if x != nil {
+		return x.Holdings
+	}
+	return nil
+}
+
+func (x *Exposure) GetSectors() []*ExposureWeight {
+	if x != nil {
+		return x.Sectors
+	}
+	return nil
+}
+
+func (x *Exposure) GetCountries() []*ExposureWeight {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+type ExposureWeight struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Share of the portfolio, in percent.
+	Weight float64 `protobuf:"fixed64,2,opt,name=weight,proto3" json:"weight,omitempty"`
+}
+
+func (x *ExposureWeight) Reset() {
+	*x = ExposureWeight{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExposureWeight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExposureWeight) ProtoMessage() {}
+
+func (x *ExposureWeight) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExposureWeight.ProtoReflect.Descriptor instead.
+func (*ExposureWeight) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ExposureWeight) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExposureWeight) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type GetTrackingRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ticker string `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+}
+
+func (x *GetTrackingRequest) Reset() {
+	*x = GetTrackingRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTrackingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTrackingRequest) ProtoMessage() {}
+
+func (x *GetTrackingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTrackingRequest.ProtoReflect.Descriptor instead.
+func (*GetTrackingRequest) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetTrackingRequest) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+type Tracking struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ticker    string `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Benchmark string `protobuf:"bytes,2,opt,name=benchmark,proto3" json:"benchmark,omitempty"`
+	// Sums of the top holdings weights, in percent.
+	FundWeight  float64 `protobuf:"fixed64,3,opt,name=fund_weight,json=fundWeight,proto3" json:"fund_weight,omitempty"`
+	IndexWeight float64 `protobuf:"fixed64,4,opt,name=index_weight,json=indexWeight,proto3" json:"index_weight,omitempty"`
+	// Sum of the absolute differences of the holdings both lists show, in percent.
+	Deviation float64            `protobuf:"fixed64,5,opt,name=deviation,proto3" json:"deviation,omitempty"`
+	Holdings  []*TrackingHolding `protobuf:"bytes,6,rep,name=holdings,proto3" json:"holdings,omitempty"`
+}
+
+func (x *Tracking) Reset() {
+	*x = Tracking{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Tracking) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tracking) ProtoMessage() {}
+
+func (x *Tracking) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tracking.ProtoReflect.Descriptor instead.
+func (*Tracking) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Tracking) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *Tracking) GetBenchmark() string {
+	if x != nil {
+		return x.Benchmark
+	}
+	return ""
+}
+
+func (x *Tracking) GetFundWeight() float64 {
+	if x != nil {
+		return x.FundWeight
+	}
+	return 0
+}
+
+func (x *Tracking) GetIndexWeight() float64 {
+	if x != nil {
+		return x.IndexWeight
+	}
+	return 0
+}
+
+func (x *Tracking) GetDeviation() float64 {
+	if x != nil {
+		return x.Deviation
+	}
+	return 0
+}
+
+func (x *Tracking) GetHoldings() []*TrackingHolding {
+	if x != nil {
+		return x.Holdings
+	}
+	return nil
+}
+
+type TrackingHolding struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Unset when the holding is not among the top holdings of the fund or the index.
+	FundWeight  *float64 `protobuf:"fixed64,2,opt,name=fund_weight,json=fundWeight,proto3,oneof" json:"fund_weight,omitempty"`
+	IndexWeight *float64 `protobuf:"fixed64,3,opt,name=index_weight,json=indexWeight,proto3,oneof" json:"index_weight,omitempty"`
+	// Fund weight minus index weight, unset unless both are known.
+	Difference *float64 `protobuf:"fixed64,4,opt,name=difference,proto3,oneof" json:"difference,omitempty"`
+}
+
+func (x *TrackingHolding) Reset() {
+	*x = TrackingHolding{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_etfpb_etf_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrackingHolding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackingHolding) ProtoMessage() {}
+
+func (x *TrackingHolding) ProtoReflect() protoreflect.Message {
+	mi := &file_etfpb_etf_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackingHolding.ProtoReflect.Descriptor instead.
+func (*TrackingHolding) Descriptor() ([]byte, []int) {
+	return file_etfpb_etf_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TrackingHolding) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TrackingHolding) GetFundWeight() float64 {
+	if x != nil && x.FundWeight != nil {
+		return *x.FundWeight
+	}
+	return 0
+}
+
+func (x *TrackingHolding) GetIndexWeight() float64 {
+	if x != nil && x.IndexWeight != nil {
+		return *x.IndexWeight
+	}
+	return 0
+}
+
+func (x *TrackingHolding) GetDifference() float64 {
+	if x != nil && x.Difference != nil {
+		return *x.Difference
+	}
+	return 0
+}
+
+var File_etfpb_etf_proto protoreflect.FileDescriptor
+
+var file_etfpb_etf_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x65, 0x74, 0x66, 0x70, 0x62, 0x2f, 0x65, 0x74, 0x66, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x06, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x11, 0x0a, 0x0f, 0x4c, 0x69,
+	0x73, 0x74, 0x45, 0x54, 0x46, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x2c, 0x0a,
+	0x10, 0x4c, 0x69, 0x73, 0x74, 0x45, 0x54, 0x46, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x73, 0x22, 0x27, 0x0a, 0x0d, 0x47,
+	0x65, 0x74, 0x45, 0x54, 0x46, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x69,
+	0x63, 0x6b, 0x65, 0x72, 0x22, 0x2f, 0x0a, 0x13, 0x57, 0x61, 0x74, 0x63, 0x68, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x74,
+	0x69, 0x63, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x74, 0x69,
+	0x63, 0x6b, 0x65, 0x72, 0x73, 0x22, 0x65, 0x0a, 0x09, 0x45, 0x54, 0x46, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x12, 0x1d, 0x0a, 0x03, 0x65, 0x74, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0b, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x54, 0x46, 0x52, 0x03, 0x65, 0x74,
+	0x66, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xe1, 0x03, 0x0a,
+	0x03, 0x45, 0x54, 0x46, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x0c, 0x74, 0x6f,
+	0x70, 0x5f, 0x68, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x6f, 0x6c, 0x64, 0x69, 0x6e,
+	0x67, 0x52, 0x0b, 0x74, 0x6f, 0x70, 0x48, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x30,
+	0x0a, 0x09, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x09, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x12, 0x2c, 0x0a, 0x07, 0x73, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x07, 0x73, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x45,
+	0x0a, 0x0f, 0x63, 0x68, 0x61, 0x72, 0x61, 0x63, 0x74, 0x65, 0x72, 0x69, 0x73, 0x74, 0x69, 0x63,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31,
+	0x2e, 0x46, 0x75, 0x6e, 0x64, 0x43, 0x68, 0x61, 0x72, 0x61, 0x63, 0x74, 0x65, 0x72, 0x69, 0x73,
+	0x74, 0x69, 0x63, 0x73, 0x52, 0x0f, 0x63, 0x68, 0x61, 0x72, 0x61, 0x63, 0x74, 0x65, 0x72, 0x69,
+	0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x3d, 0x0a, 0x12, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x74,
+	0x6f, 0x70, 0x5f, 0x68, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x6f, 0x6c, 0x64, 0x69,
+	0x6e, 0x67, 0x52, 0x10, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x54, 0x6f, 0x70, 0x48, 0x6f, 0x6c, 0x64,
+	0x69, 0x6e, 0x67, 0x73, 0x12, 0x31, 0x0a, 0x0a, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x64, 0x6f, 0x77,
+	0x6e, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x64, 0x6f, 0x77, 0x6e, 0x52, 0x0a, 0x62, 0x72, 0x65,
+	0x61, 0x6b, 0x64, 0x6f, 0x77, 0x6e, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x73,
+	0x73, 0x65, 0x74, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x36, 0x0a, 0x0c, 0x66, 0x69, 0x78, 0x65,
+	0x64, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x78, 0x65, 0x64, 0x49, 0x6e, 0x63,
+	0x6f, 0x6d, 0x65, 0x52, 0x0b, 0x66, 0x69, 0x78, 0x65, 0x64, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65,
+	0x22, 0x93, 0x03, 0x0a, 0x13, 0x46, 0x75, 0x6e, 0x64, 0x43, 0x68, 0x61, 0x72, 0x61, 0x63, 0x74,
+	0x65, 0x72, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x15, 0x0a, 0x03, 0x6e, 0x61, 0x76, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x03, 0x6e, 0x61, 0x76, 0x88, 0x01, 0x01, 0x12,
+	0x26, 0x0a, 0x0c, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x0b, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x50,
+	0x72, 0x69, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x13, 0x67, 0x72, 0x6f, 0x73, 0x73,
+	0x5f, 0x65, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x01, 0x48, 0x02, 0x52, 0x11, 0x67, 0x72, 0x6f, 0x73, 0x73, 0x45, 0x78, 0x70,
+	0x65, 0x6e, 0x73, 0x65, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03,
+	0x61, 0x75, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x48, 0x03, 0x52, 0x03, 0x61, 0x75, 0x6d,
+	0x88, 0x01, 0x01, 0x12, 0x31, 0x0a, 0x12, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x6f, 0x66,
+	0x5f, 0x68, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x04, 0x52, 0x10, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x4f, 0x66, 0x48, 0x6f, 0x6c, 0x64, 0x69,
+	0x6e, 0x67, 0x73, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x63, 0x65, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x69, 0x6e, 0x63, 0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a,
+	0x09, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x12, 0x29, 0x0a, 0x10, 0x70,
+	0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x5f, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x45, 0x78,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6e, 0x61, 0x76, 0x42, 0x0f,
+	0x0a, 0x0d, 0x5f, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x42,
+	0x16, 0x0a, 0x14, 0x5f, 0x67, 0x72, 0x6f, 0x73, 0x73, 0x5f, 0x65, 0x78, 0x70, 0x65, 0x6e, 0x73,
+	0x65, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x61, 0x75, 0x6d, 0x42,
+	0x15, 0x0a, 0x13, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x6f, 0x66, 0x5f, 0x68, 0x6f,
+	0x6c, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x22, 0xb8, 0x02, 0x0a, 0x0b, 0x46, 0x69, 0x78, 0x65, 0x64,
+	0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x11, 0x79, 0x69, 0x65, 0x6c, 0x64,
+	0x5f, 0x74, 0x6f, 0x5f, 0x6d, 0x61, 0x74, 0x75, 0x72, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x48, 0x01, 0x52, 0x0f, 0x79, 0x69, 0x65, 0x6c, 0x64, 0x54, 0x6f, 0x4d, 0x61, 0x74,
+	0x75, 0x72, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0e, 0x79, 0x69, 0x65, 0x6c,
+	0x64, 0x5f, 0x74, 0x6f, 0x5f, 0x77, 0x6f, 0x72, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x02, 0x52, 0x0c, 0x79, 0x69, 0x65, 0x6c, 0x64, 0x54, 0x6f, 0x57, 0x6f, 0x72, 0x73, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x39, 0x0a, 0x0e, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x5f, 0x71, 0x75,
+	0x61, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x65, 0x74,
+	0x66, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52,
+	0x0d, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x3b,
+	0x0a, 0x0f, 0x6d, 0x61, 0x74, 0x75, 0x72, 0x69, 0x74, 0x79, 0x5f, 0x6c, 0x61, 0x64, 0x64, 0x65,
+	0x72, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31,
+	0x2e, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x0e, 0x6d, 0x61, 0x74,
+	0x75, 0x72, 0x69, 0x74, 0x79, 0x4c, 0x61, 0x64, 0x64, 0x65, 0x72, 0x42, 0x0b, 0x0a, 0x09, 0x5f,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x79, 0x69, 0x65,
+	0x6c, 0x64, 0x5f, 0x74, 0x6f, 0x5f, 0x6d, 0x61, 0x74, 0x75, 0x72, 0x69, 0x74, 0x79, 0x42, 0x11,
+	0x0a, 0x0f, 0x5f, 0x79, 0x69, 0x65, 0x6c, 0x64, 0x5f, 0x74, 0x6f, 0x5f, 0x77, 0x6f, 0x72, 0x73,
+	0x74, 0x22, 0x56, 0x0a, 0x07, 0x48, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x5f, 0x68, 0x65, 0x6c, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x48, 0x65, 0x6c,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x38, 0x0a, 0x0a, 0x57, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x77,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x22, 0x4f, 0x0a, 0x09, 0x42, 0x72, 0x65, 0x61, 0x6b, 0x64, 0x6f, 0x77, 0x6e,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x2c, 0x0a, 0x07, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31,
+	0x2e, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x07, 0x77, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x73, 0x22, 0x2d, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x6c,
+	0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x63,
+	0x6b, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x74, 0x69, 0x63, 0x6b,
+	0x65, 0x72, 0x73, 0x22, 0x6f, 0x0a, 0x07, 0x4f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x70, 0x12, 0x18,
+	0x0a, 0x07, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x07, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x32, 0x0a, 0x08, 0x68, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x76, 0x65, 0x72,
+	0x6c, 0x61, 0x70, 0x48, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x68, 0x6f, 0x6c, 0x64,
+	0x69, 0x6e, 0x67, 0x73, 0x22, 0xb7, 0x01, 0x0a, 0x0e, 0x4f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x70,
+	0x48, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x77,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x12, 0x3d, 0x0a, 0x07, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x76,
+	0x65, 0x72, 0x6c, 0x61, 0x70, 0x48, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x57, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x77, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x73, 0x1a, 0x3a, 0x0a, 0x0c, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x44,
+	0x0a, 0x12, 0x47, 0x65, 0x74, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x09, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0x3a, 0x0a, 0x08, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x16, 0x0a, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x22, 0xa6, 0x01, 0x0a, 0x08, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x12, 0x32, 0x0a,
+	0x08, 0x68, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72,
+	0x65, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x52, 0x08, 0x68, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67,
+	0x73, 0x12, 0x30, 0x0a, 0x07, 0x73, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x6f,
+	0x73, 0x75, 0x72, 0x65, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x52, 0x07, 0x73, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x73, 0x12, 0x34, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x52, 0x09,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x3c, 0x0a, 0x0e, 0x45, 0x78, 0x70,
+	0x6f, 0x73, 0x75, 0x72, 0x65, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x2c, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x54, 0x72,
+	0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74,
+	0x69, 0x63, 0x6b, 0x65, 0x72, 0x22, 0xd7, 0x01, 0x0a, 0x08, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x69,
+	0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x65,
+	0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62,
+	0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x75, 0x6e, 0x64,
+	0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x66,
+	0x75, 0x6e, 0x64, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0b, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x64, 0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x09, 0x64, 0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x33, 0x0a, 0x08, 0x68, 0x6f,
+	0x6c, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x65,
+	0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x48, 0x6f,
+	0x6c, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x68, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x22,
+	0xc8, 0x01, 0x0a, 0x0f, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x48, 0x6f, 0x6c, 0x64,
+	0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0b, 0x66, 0x75, 0x6e, 0x64, 0x5f,
+	0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0a,
+	0x66, 0x75, 0x6e, 0x64, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a,
+	0x0c, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x0b, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x57, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x64, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x48, 0x02, 0x52, 0x0a, 0x64, 0x69, 0x66,
+	0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x66,
+	0x75, 0x6e, 0x64, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x42, 0x0d, 0x0a, 0x0b, 0x5f,
+	0x64, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x32, 0xef, 0x02, 0x0a, 0x0a, 0x45,
+	0x54, 0x46, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3d, 0x0a, 0x08, 0x4c, 0x69, 0x73,
+	0x74, 0x45, 0x54, 0x46, 0x73, 0x12, 0x17, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x45, 0x54, 0x46, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18,
+	0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x45, 0x54, 0x46, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x47, 0x65, 0x74, 0x45,
+	0x54, 0x46, 0x12, 0x15, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x45,
+	0x54, 0x46, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0b, 0x2e, 0x65, 0x74, 0x66, 0x2e,
+	0x76, 0x31, 0x2e, 0x45, 0x54, 0x46, 0x12, 0x40, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x54, 0x46,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x30, 0x01, 0x12, 0x38, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x4f,
+	0x76, 0x65, 0x72, 0x6c, 0x61, 0x70, 0x12, 0x19, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x0f, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x76, 0x65, 0x72, 0x6c,
+	0x61, 0x70, 0x12, 0x3b, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72,
+	0x65, 0x12, 0x1a, 0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x78,
+	0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e,
+	0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x12,
+	0x3b, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x12, 0x1a,
+	0x2e, 0x65, 0x74, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x63, 0x6b,
+	0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x65, 0x74, 0x66,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x42, 0x1c, 0x5a, 0x1a,
+	0x61, 0x77, 0x65, 0x73, 0x6f, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x74, 0x66, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_etfpb_etf_proto_rawDescOnce sync.Once
+	file_etfpb_etf_proto_rawDescData = file_etfpb_etf_proto_rawDesc
+)
+
+func file_etfpb_etf_proto_rawDescGZIP() []byte {
+	file_etfpb_etf_proto_rawDescOnce.Do(func() {
+		file_etfpb_etf_proto_rawDescData = protoimpl.X.CompressGZIP(file_etfpb_etf_proto_rawDescData)
+	})
+	return file_etfpb_etf_proto_rawDescData
+}
+
+var file_etfpb_etf_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_etfpb_etf_proto_goTypes = []interface{}{
+	(*ListETFsRequest)(nil),       // 0: etf.v1.ListETFsRequest
+	(*ListETFsResponse)(nil),      // 1: etf.v1.ListETFsResponse
+	(*GetETFRequest)(nil),         // 2: etf.v1.GetETFRequest
+	(*WatchUpdatesRequest)(nil),   // 3: etf.v1.WatchUpdatesRequest
+	(*ETFUpdate)(nil),             // 4: etf.v1.ETFUpdate
+	(*ETF)(nil),                   // 5: etf.v1.ETF
+	(*FundCharacteristics)(nil),   // 6: etf.v1.FundCharacteristics
+	(*FixedIncome)(nil),           // 7: etf.v1.FixedIncome
+	(*Holding)(nil),               // 8: etf.v1.Holding
+	(*WeightData)(nil),            // 9: etf.v1.WeightData
+	(*Breakdown)(nil),             // 10: etf.v1.Breakdown
+	(*GetOverlapRequest)(nil),     // 11: etf.v1.GetOverlapRequest
+	(*Overlap)(nil),               // 12: etf.v1.Overlap
+	(*OverlapHolding)(nil),        // 13: etf.v1.OverlapHolding
+	(*GetExposureRequest)(nil),    // 14: etf.v1.GetExposureRequest
+	(*Position)(nil),              // 15: etf.v1.Position
+	(*Exposure)(nil),              // 16: etf.v1.Exposure
+	(*ExposureWeight)(nil),        // 17: etf.v1.ExposureWeight
+	(*GetTrackingRequest)(nil),    // 18: etf.v1.GetTrackingRequest
+	(*Tracking)(nil),              // 19: etf.v1.Tracking
+	(*TrackingHolding)(nil),       // 20: etf.v1.TrackingHolding
+	nil,                           // 21: etf.v1.OverlapHolding.WeightsEntry
+	(*timestamppb.Timestamp)(nil), // 22: google.protobuf.Timestamp
+}
+var file_etfpb_etf_proto_depIdxs = []int32{
+	5,  // 0: etf.v1.ETFUpdate.etf:type_name -> etf.v1.ETF
+	22, // 1: etf.v1.ETFUpdate.updated_at:type_name -> google.protobuf.Timestamp
+	8,  // 2: etf.v1.ETF.top_holdings:type_name -> etf.v1.Holding
+	9,  // 3: etf.v1.ETF.countries:type_name -> etf.v1.WeightData
+	9,  // 4: etf.v1.ETF.sectors:type_name -> etf.v1.WeightData
+	6,  // 5: etf.v1.ETF.characteristics:type_name -> etf.v1.FundCharacteristics
+	8,  // 6: etf.v1.ETF.index_top_holdings:type_name -> etf.v1.Holding
+	10, // 7: etf.v1.ETF.breakdowns:type_name -> etf.v1.Breakdown
+	7,  // 8: etf.v1.ETF.fixed_income:type_name -> etf.v1.FixedIncome
+	9,  // 9: etf.v1.FixedIncome.credit_quality:type_name -> etf.v1.WeightData
+	9,  // 10: etf.v1.FixedIncome.maturity_ladder:type_name -> etf.v1.WeightData
+	9,  // 11: etf.v1.Breakdown.weights:type_name -> etf.v1.WeightData
+	13, // 12: etf.v1.Overlap.holdings:type_name -> etf.v1.OverlapHolding
+	21, // 13: etf.v1.OverlapHolding.weights:type_name -> etf.v1.OverlapHolding.WeightsEntry
+	15, // 14: etf.v1.GetExposureRequest.positions:type_name -> etf.v1.Position
+	17, // 15: etf.v1.Exposure.holdings:type_name -> etf.v1.ExposureWeight
+	17, // 16: etf.v1.Exposure.sectors:type_name -> etf.v1.ExposureWeight
+	17, // 17: etf.v1.Exposure.countries:type_name -> etf.v1.ExposureWeight
+	20, // 18: etf.v1.Tracking.holdings:type_name -> etf.v1.TrackingHolding
+	0,  // 19: etf.v1.ETFService.ListETFs:input_type -> etf.v1.ListETFsRequest
+	2,  // 20: etf.v1.ETFService.GetETF:input_type -> etf.v1.GetETFRequest
+	3,  // 21: etf.v1.ETFService.WatchUpdates:input_type -> etf.v1.WatchUpdatesRequest
+	11, // 22: etf.v1.ETFService.GetOverlap:input_type -> etf.v1.GetOverlapRequest
+	14, // 23: etf.v1.ETFService.GetExposure:input_type -> etf.v1.GetExposureRequest
+	18, // 24: etf.v1.ETFService.GetTracking:input_type -> etf.v1.GetTrackingRequest
+	1,  // 25: etf.v1.ETFService.ListETFs:output_type -> etf.v1.ListETFsResponse
+	5,  // 26: etf.v1.ETFService.GetETF:output_type -> etf.v1.ETF
+	4,  // 27: etf.v1.ETFService.WatchUpdates:output_type -> etf.v1.ETFUpdate
+	12, // 28: etf.v1.ETFService.GetOverlap:output_type -> etf.v1.Overlap
+	16, // 29: etf.v1.ETFService.GetExposure:output_type -> etf.v1.Exposure
+	19, // 30: etf.v1.ETFService.GetTracking:output_type -> etf.v1.Tracking
+	25, // [25:31] is the sub-list for method output_type
+	19, // [19:25] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
+}
+
+func init() { file_etfpb_etf_proto_init() }
+func file_etfpb_etf_proto_init() {
+	if File_etfpb_etf_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_etfpb_etf_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListETFsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListETFsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetETFRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchUpdatesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ETFUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ETF); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FundCharacteristics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FixedIncome); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Holding); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WeightData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Breakdown); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOverlapRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Overlap); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OverlapHolding); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetExposureRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Position); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Exposure); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExposureWeight); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTrackingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Tracking); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_etfpb_etf_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrackingHolding); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_etfpb_etf_proto_msgTypes[6].OneofWrappers = []interface{}{}
+	file_etfpb_etf_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	file_etfpb_etf_proto_msgTypes[20].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_etfpb_etf_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_etfpb_etf_proto_goTypes,
+		DependencyIndexes: file_etfpb_etf_proto_depIdxs,
+		MessageInfos:      file_etfpb_etf_proto_msgTypes,
+	}.Build()
+	File_etfpb_etf_proto = out.File
+	file_etfpb_etf_proto_rawDesc = nil
+	file_etfpb_etf_proto_goTypes = nil
+	file_etfpb_etf_proto_depIdxs = nil
+}
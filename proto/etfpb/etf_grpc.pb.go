@@ -0,0 +1,340 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: etfpb/etf.proto
+
+package etfpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ETFService_ListETFs_FullMethodName     = "/etf.v1.ETFService/ListETFs"
+	ETFService_GetETF_FullMethodName       = "/etf.v1.ETFService/GetETF"
+	ETFService_WatchUpdates_FullMethodName = "/etf.v1.ETFService/WatchUpdates"
+	ETFService_GetOverlap_FullMethodName   = "/etf.v1.ETFService/GetOverlap"
+	ETFService_GetExposure_FullMethodName  = "/etf.v1.ETFService/GetExposure"
+	ETFService_GetTracking_FullMethodName  = "/etf.v1.ETFService/GetTracking"
+)
+
+// ETFServiceClient is the client API for ETFService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ETFServiceClient interface {
+	// ListETFs returns the tickers of all stored ETFs.
+	ListETFs(ctx context.Context, in *ListETFsRequest, opts ...grpc.CallOption) (*ListETFsResponse, error)
+	// GetETF returns the data of one ETF.
+	GetETF(ctx context.Context, in *GetETFRequest, opts ...grpc.CallOption) (*ETF, error)
+	// WatchUpdates streams ETFs as the updater stores new data for them.
+	WatchUpdates(ctx context.Context, in *WatchUpdatesRequest, opts ...grpc.CallOption) (ETFService_WatchUpdatesClient, error)
+	// GetOverlap returns the top holdings two or more ETFs have in common.
+	// Requires at least the analyst role.
+	GetOverlap(ctx context.Context, in *GetOverlapRequest, opts ...grpc.CallOption) (*Overlap, error)
+	// GetExposure returns the combined holdings, sectors and countries of a
+	// portfolio of ETFs. Requires at least the analyst role.
+	GetExposure(ctx context.Context, in *GetExposureRequest, opts ...grpc.CallOption) (*Exposure, error)
+	// GetTracking compares the top holdings of an ETF with those of its
+	// benchmark index. Requires at least the analyst role.
+	GetTracking(ctx context.Context, in *GetTrackingRequest, opts ...grpc.CallOption) (*Tracking, error)
+}
+
+type eTFServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewETFServiceClient(cc grpc.ClientConnInterface) ETFServiceClient {
+	return &eTFServiceClient{cc}
+}
+
+func (c *eTFServiceClient) ListETFs(ctx context.Context, in *ListETFsRequest, opts ...grpc.CallOption) (*ListETFsResponse, error) {
+	out := new(ListETFsResponse)
+	err := c.cc.Invoke(ctx, ETFService_ListETFs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eTFServiceClient) GetETF(ctx context.Context, in *GetETFRequest, opts ...grpc.CallOption) (*ETF, error) {
+	out := new(ETF)
+	err := c.cc.Invoke(ctx, ETFService_GetETF_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eTFServiceClient) WatchUpdates(ctx context.Context, in *WatchUpdatesRequest, opts ...grpc.CallOption) (ETFService_WatchUpdatesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ETFService_ServiceDesc.Streams[0], ETFService_WatchUpdates_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eTFServiceWatchUpdatesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ETFService_WatchUpdatesClient interface {
+	Recv() (*ETFUpdate, error)
+	grpc.ClientStream
+}
+
+type eTFServiceWatchUpdatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *eTFServiceWatchUpdatesClient) Recv() (*ETFUpdate, error) {
+	m := new(ETFUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *eTFServiceClient) GetOverlap(ctx context.Context, in *GetOverlapRequest, opts ...grpc.CallOption) (*Overlap, error) {
+	out := new(Overlap)
+	err := c.cc.Invoke(ctx, ETFService_GetOverlap_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eTFServiceClient) GetExposure(ctx context.Context, in *GetExposureRequest, opts ...grpc.CallOption) (*Exposure, error) {
+	out := new(Exposure)
+	err := c.cc.Invoke(ctx, ETFService_GetExposure_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eTFServiceClient) GetTracking(ctx context.Context, in *GetTrackingRequest, opts ...grpc.CallOption) (*Tracking, error) {
+	out := new(Tracking)
+	err := c.cc.Invoke(ctx, ETFService_GetTracking_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ETFServiceServer is the server API for ETFService service.
+// All implementations must embed UnimplementedETFServiceServer
+// for forward compatibility
+type ETFServiceServer interface {
+	// ListETFs returns the tickers of all stored ETFs.
+	ListETFs(context.Context, *ListETFsRequest) (*ListETFsResponse, error)
+	// GetETF returns the data of one ETF.
+	GetETF(context.Context, *GetETFRequest) (*ETF, error)
+	// WatchUpdates streams ETFs as the updater stores new data for them.
+	WatchUpdates(*WatchUpdatesRequest, ETFService_WatchUpdatesServer) error
+	// GetOverlap returns the top holdings two or more ETFs have in common.
+	// Requires at least the analyst role.
+	GetOverlap(context.Context, *GetOverlapRequest) (*Overlap, error)
+	// GetExposure returns the combined holdings, sectors and countries of a
+	// portfolio of ETFs. Requires at least the analyst role.
+	GetExposure(context.Context, *GetExposureRequest) (*Exposure, error)
+	// GetTracking compares the top holdings of an ETF with those of its
+	// benchmark index. Requires at least the analyst role.
+	GetTracking(context.Context, *GetTrackingRequest) (*Tracking, error)
+	mustEmbedUnimplementedETFServiceServer()
+}
+
+// UnimplementedETFServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedETFServiceServer struct {
+}
+
+func (UnimplementedETFServiceServer) ListETFs(context.Context, *ListETFsRequest) (*ListETFsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListETFs not implemented")
+}
+func (UnimplementedETFServiceServer) GetETF(context.Context, *GetETFRequest) (*ETF, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetETF not implemented")
+}
+func (UnimplementedETFServiceServer) WatchUpdates(*WatchUpdatesRequest, ETFService_WatchUpdatesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUpdates not implemented")
+}
+func (UnimplementedETFServiceServer) GetOverlap(context.Context, *GetOverlapRequest) (*Overlap, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOverlap not implemented")
+}
+func (UnimplementedETFServiceServer) GetExposure(context.Context, *GetExposureRequest) (*Exposure, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExposure not implemented")
+}
+func (UnimplementedETFServiceServer) GetTracking(context.Context, *GetTrackingRequest) (*Tracking, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTracking not implemented")
+}
+func (UnimplementedETFServiceServer) mustEmbedUnimplementedETFServiceServer() {}
+
+// UnsafeETFServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ETFServiceServer will
+// result in compilation errors.
+type UnsafeETFServiceServer interface {
+	mustEmbedUnimplementedETFServiceServer()
+}
+
+func RegisterETFServiceServer(s grpc.ServiceRegistrar, srv ETFServiceServer) {
+	s.RegisterService(&ETFService_ServiceDesc, srv)
+}
+
+func _ETFService_ListETFs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListETFsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ETFServiceServer).ListETFs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ETFService_ListETFs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ETFServiceServer).ListETFs(ctx, req.(*ListETFsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ETFService_GetETF_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetETFRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ETFServiceServer).GetETF(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ETFService_GetETF_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ETFServiceServer).GetETF(ctx, req.(*GetETFRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ETFService_WatchUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUpdatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ETFServiceServer).WatchUpdates(m, &eTFServiceWatchUpdatesServer{stream})
+}
+
+type ETFService_WatchUpdatesServer interface {
+	Send(*ETFUpdate) error
+	grpc.ServerStream
+}
+
+type eTFServiceWatchUpdatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *eTFServiceWatchUpdatesServer) Send(m *ETFUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ETFService_GetOverlap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOverlapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ETFServiceServer).GetOverlap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ETFService_GetOverlap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ETFServiceServer).GetOverlap(ctx, req.(*GetOverlapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ETFService_GetExposure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExposureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ETFServiceServer).GetExposure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ETFService_GetExposure_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ETFServiceServer).GetExposure(ctx, req.(*GetExposureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ETFService_GetTracking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTrackingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ETFServiceServer).GetTracking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ETFService_GetTracking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ETFServiceServer).GetTracking(ctx, req.(*GetTrackingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ETFService_ServiceDesc is the grpc.ServiceDesc for ETFService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ETFService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "etf.v1.ETFService",
+	HandlerType: (*ETFServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListETFs",
+			Handler:    _ETFService_ListETFs_Handler,
+		},
+		{
+			MethodName: "GetETF",
+			Handler:    _ETFService_GetETF_Handler,
+		},
+		{
+			MethodName: "GetOverlap",
+			Handler:    _ETFService_GetOverlap_Handler,
+		},
+		{
+			MethodName: "GetExposure",
+			Handler:    _ETFService_GetExposure_Handler,
+		},
+		{
+			MethodName: "GetTracking",
+			Handler:    _ETFService_GetTracking_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUpdates",
+			Handler:       _ETFService_WatchUpdates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "etfpb/etf.proto",
+}